@@ -0,0 +1,127 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+// api serves the JSON API under /api/v1. Every route but /health needs an
+// "Authorization: Bearer <token>" header with a token from create-token.
+type api struct {
+	s *state
+}
+
+func (a *api) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/health", a.handleHealth)
+	mux.HandleFunc("GET /api/v1/status", a.authenticated(a.handleStatus))
+	mux.HandleFunc("GET /api/v1/me", a.authenticated(a.handleMe))
+	mux.HandleFunc("GET /api/v1/users", a.authenticated(a.handleUsers))
+	mux.HandleFunc("GET /api/v1/feeds", a.authenticated(a.handleFeeds))
+	mux.HandleFunc("POST /api/v1/feeds", a.authenticated(a.handleAddFeed))
+	mux.HandleFunc("GET /api/v1/feeds/errors", a.authenticated(a.handleFeedErrors))
+	mux.HandleFunc("GET /api/v1/follows", a.authenticated(a.handleFollows))
+	mux.HandleFunc("POST /api/v1/follows", a.authenticated(a.handleFollow))
+	mux.HandleFunc("DELETE /api/v1/follows/{feedID}", a.authenticated(a.handleUnfollow))
+	mux.HandleFunc("GET /api/v1/posts", a.authenticated(a.handlePosts))
+	mux.HandleFunc("GET /api/v1/posts/{postID}", a.authenticated(a.handlePost))
+	mux.HandleFunc("PUT /api/v1/posts/{postID}/read", a.authenticated(a.handleMarkRead))
+	mux.HandleFunc("DELETE /api/v1/posts/{postID}/read", a.authenticated(a.handleMarkUnread))
+	mux.HandleFunc("PUT /api/v1/posts/{postID}/star", a.authenticated(a.handleStar))
+	mux.HandleFunc("DELETE /api/v1/posts/{postID}/star", a.authenticated(a.handleUnstar))
+	mux.HandleFunc("GET /api/v1/search", a.authenticated(a.handleSearch))
+	(&greaderAPI{s: a.s}).register(mux)
+	(&feverAPI{s: a.s}).register(mux)
+	newWebUI(a.s).register(mux)
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		respondError(w, http.StatusNotFound, "no such endpoint")
+	})
+	return mux
+}
+
+// authenticated is the API's middlewareLoggedIn: it resolves the bearer token
+// to a user instead of reading current_user_name from the config.
+func (a *api) authenticated(handler func(w http.ResponseWriter, r *http.Request, user database.User)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gator"`)
+			respondError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		hash := hashAPIToken(strings.TrimSpace(token))
+		user, err := a.s.db.GetUserByApiToken(r.Context(), hash)
+		if errors.Is(err, sql.ErrNoRows) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gator", error="invalid_token"`)
+			respondError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		if err != nil {
+			respondServerError(w, err)
+			return
+		}
+
+		// Not worth failing the request over.
+		err = a.s.db.TouchApiToken(r.Context(),
+			database.TouchApiTokenParams{
+				TokenHash:  hash,
+				LastUsedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			},
+		)
+		if err != nil {
+			log.Printf("error recording token use: %v", err)
+		}
+
+		handler(w, r, user)
+	}
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func respondJSON(w http.ResponseWriter, code int, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("error encoding response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+func respondError(w http.ResponseWriter, code int, msg string) {
+	respondJSON(w, code, apiError{Error: msg})
+}
+
+// respondServerError logs err and keeps its details out of the response.
+// Rows that don't exist are the caller's mistake and answered with a 404.
+func respondServerError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "not found")
+		return
+	}
+	log.Printf("api error: %v", err)
+	respondError(w, http.StatusInternalServerError, "internal server error")
+}
+
+// decodeJSON reads a request body of at most 1MB into v.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,492 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// The API's JSON shapes. Database rows aren't encoded directly so NULL
+// columns come out as missing fields rather than {"String":..,"Valid":..}.
+
+type apiUser struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type apiFeed struct {
+	ID                  uuid.UUID  `json:"id"`
+	Name                string     `json:"name"`
+	URL                 string     `json:"url"`
+	Link                string     `json:"link,omitempty"`
+	Description         string     `json:"description,omitempty"`
+	UserID              uuid.UUID  `json:"user_id"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	LastFetchedAt       *time.Time `json:"last_fetched_at,omitempty"`
+	NextFetchAt         *time.Time `json:"next_fetch_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	LastStatusCode      int32      `json:"last_status_code,omitempty"`
+	ConsecutiveFailures int32      `json:"consecutive_failures"`
+}
+
+type apiFollow struct {
+	FeedID      uuid.UUID `json:"feed_id"`
+	FeedName    string    `json:"feed_name"`
+	FeedURL     string    `json:"feed_url"`
+	FeedLink    string    `json:"feed_link,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	UnreadCount int64     `json:"unread_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type apiPost struct {
+	ID            uuid.UUID `json:"id"`
+	FeedID        uuid.UUID `json:"feed_id"`
+	FeedName      string    `json:"feed_name,omitempty"`
+	Title         string    `json:"title"`
+	URL           string    `json:"url"`
+	Description   string    `json:"description,omitempty"`
+	Author        string    `json:"author,omitempty"`
+	EnclosureURL  string    `json:"enclosure_url,omitempty"`
+	EnclosureType string    `json:"enclosure_type,omitempty"`
+	PublishedAt   time.Time `json:"published_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type apiSearchResult struct {
+	apiPost
+	Rank                float32 `json:"rank"`
+	TitleHeadline       string  `json:"title_headline"`
+	DescriptionHeadline string  `json:"description_headline"`
+}
+
+type apiStatus struct {
+	Feeds         int64      `json:"feeds"`
+	Due           int64      `json:"due"`
+	Fetching      int64      `json:"fetching"`
+	Failing       int64      `json:"failing"`
+	Workers       int64      `json:"workers"`
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+	NextFetchAt   *time.Time `json:"next_fetch_at,omitempty"`
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func toAPIUser(user database.User) apiUser {
+	return apiUser{ID: user.ID, Name: user.Name, CreatedAt: user.CreatedAt}
+}
+
+func toAPIFeed(feed database.Feed) apiFeed {
+	return apiFeed{
+		ID:                  feed.ID,
+		Name:                feed.Name,
+		URL:                 feed.Url,
+		Link:                feed.Link.String,
+		Description:         feed.Description.String,
+		UserID:              feed.UserID,
+		CreatedAt:           feed.CreatedAt,
+		UpdatedAt:           feed.UpdatedAt,
+		LastFetchedAt:       nullTimePtr(feed.LastFetchedAt),
+		NextFetchAt:         nullTimePtr(feed.NextFetchAt),
+		LastError:           feed.LastError.String,
+		LastErrorAt:         nullTimePtr(feed.LastErrorAt),
+		LastStatusCode:      feed.LastStatusCode.Int32,
+		ConsecutiveFailures: feed.ConsecutiveFailures,
+	}
+}
+
+func toAPIFeeds(feeds []database.Feed) []apiFeed {
+	out := make([]apiFeed, len(feeds))
+	for i, feed := range feeds {
+		out[i] = toAPIFeed(feed)
+	}
+	return out
+}
+
+func toAPIPost(post database.Post, feedName string) apiPost {
+	return apiPost{
+		ID:            post.ID,
+		FeedID:        post.FeedID,
+		FeedName:      feedName,
+		Title:         post.Title.String,
+		URL:           post.Url,
+		Description:   post.Description.String,
+		Author:        post.Author.String,
+		EnclosureURL:  post.EnclosureUrl.String,
+		EnclosureType: post.EnclosureType.String,
+		PublishedAt:   post.PublishedAt,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+}
+
+func (a *api) handleHealth(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (a *api) handleStatus(w http.ResponseWriter, r *http.Request, user database.User) {
+	status, err := a.s.db.GetAggStatus(r.Context())
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, apiStatus{
+		Feeds:         status.Feeds,
+		Due:           status.Due,
+		Fetching:      status.Fetching,
+		Failing:       status.Failing,
+		Workers:       status.Workers,
+		LastFetchedAt: nullTimePtr(status.LastFetchedAt),
+		NextFetchAt:   nullTimePtr(status.NextFetchAt),
+	})
+}
+
+func (a *api) handleMe(w http.ResponseWriter, r *http.Request, user database.User) {
+	respondJSON(w, http.StatusOK, toAPIUser(user))
+}
+
+func (a *api) handleUsers(w http.ResponseWriter, r *http.Request, user database.User) {
+	names, err := a.s.db.GetUsers(r.Context())
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, names)
+}
+
+func (a *api) handleFeeds(w http.ResponseWriter, r *http.Request, user database.User) {
+	feeds, err := a.s.db.ListFeeds(r.Context())
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, toAPIFeeds(feeds))
+}
+
+func (a *api) handleAddFeed(w http.ResponseWriter, r *http.Request, user database.User) {
+	var body struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.URL == "" {
+		respondError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	feed, _, err := createFeed(publicOnly(r.Context()), a.s, user, body.Name, body.URL, "")
+	switch {
+	case errors.Is(err, errFeedExists):
+		respondError(w, http.StatusConflict, "feed already exists, follow it instead")
+		return
+	case errors.Is(err, errFeedNameTaken):
+		respondError(w, http.StatusConflict, "feed name already taken")
+		return
+	case errors.Is(err, errPrivateAddress):
+		respondError(w, http.StatusBadRequest, "url must point at a public address")
+		return
+	case err != nil:
+		log.Printf("api: couldn't add feed %s: %v", body.URL, err)
+		respondError(w, http.StatusBadRequest, "no feed gator can read at url")
+		return
+	}
+	respondJSON(w, http.StatusCreated, toAPIFeed(feed))
+}
+
+func (a *api) handleFeedErrors(w http.ResponseWriter, r *http.Request, user database.User) {
+	feeds, err := a.s.db.GetFeedErrors(r.Context())
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, toAPIFeeds(feeds))
+}
+
+func (a *api) handleFollows(w http.ResponseWriter, r *http.Request, user database.User) {
+	follows, err := a.s.db.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	out := make([]apiFollow, len(follows))
+	for i, follow := range follows {
+		out[i] = apiFollow{
+			FeedID:      follow.FeedID,
+			FeedName:    follow.FeedName,
+			FeedURL:     follow.FeedUrl,
+			FeedLink:    follow.FeedLink.String,
+			Category:    follow.Category.String,
+			UnreadCount: follow.UnreadCount,
+			CreatedAt:   follow.CreatedAt,
+		}
+	}
+	respondJSON(w, http.StatusOK, out)
+}
+
+func (a *api) handleFollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	var body struct {
+		URL      string `json:"url"`
+		Category string `json:"category"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.URL == "" {
+		respondError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	feed, _, err := findFeedByURL(publicOnly(r.Context()), a.s, body.URL)
+	if errors.Is(err, errPrivateAddress) {
+		respondError(w, http.StatusBadRequest, "url must point at a public address")
+		return
+	}
+	if err != nil {
+		log.Printf("api: couldn't find feed %s: %v", body.URL, err)
+		respondError(w, http.StatusNotFound, "no feed with that url")
+		return
+	}
+	follow, err := followFeed(a.s, user, feed.ID, body.Category)
+	if isUniqueViolation(err, "feed_follows_user_id_feed_id_key") {
+		respondError(w, http.StatusConflict, "already following "+feed.Name)
+		return
+	}
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusCreated, apiFollow{
+		FeedID:    follow.FeedID,
+		FeedName:  follow.FeedName,
+		FeedURL:   feed.Url,
+		Category:  follow.Category.String,
+		CreatedAt: follow.CreatedAt,
+	})
+}
+
+func (a *api) handleUnfollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedID, ok := pathUUID(w, r, "feedID")
+	if !ok {
+		return
+	}
+	err := a.s.db.DeleteFeedFollow(r.Context(),
+		database.DeleteFeedFollowParams{UserID: user.ID, FeedID: feedID},
+	)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePosts is browse over HTTP. It takes the same options as query
+// parameters: feed (repeatable), since, until, all, unread, starred, search,
+// sort, limit, page, before and after.
+func (a *api) handlePosts(w http.ResponseWriter, r *http.Request, user database.User) {
+	query := r.URL.Query()
+	opts := timelineOptions{
+		All:     query.Get("all") == "true",
+		Unread:  query.Get("unread") == "true",
+		Starred: query.Get("starred") == "true",
+		Feeds:   query["feed"],
+		Since:   query.Get("since"),
+		Until:   query.Get("until"),
+		Search:  query.Get("search"),
+		Sort:    query.Get("sort"),
+		Before:  query.Get("before"),
+		After:   query.Get("after"),
+		Limit:   20,
+		Page:    1,
+	}
+	var ok bool
+	if opts.Limit, ok = queryInt(w, r, "limit", opts.Limit, maxPageSize); !ok {
+		return
+	}
+	if opts.Page, ok = queryInt(w, r, "page", opts.Page, 0); !ok {
+		return
+	}
+
+	params, err := timelineParams(a.s, user, opts)
+	if err != nil {
+		log.Printf("api: bad timeline options: %v", err)
+		respondError(w, http.StatusBadRequest, "invalid or unknown feed, search, sort or paging option")
+		return
+	}
+	posts, err := a.s.db.GetTimelineForUser(r.Context(), params)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	if params.Ascending {
+		slices.Reverse(posts)
+	}
+
+	out := make([]apiPost, len(posts))
+	for i, post := range posts {
+		out[i] = toAPIPost(post.Post, post.FeedName)
+	}
+	respondJSON(w, http.StatusOK, out)
+}
+
+func (a *api) handlePost(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, ok := pathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	post, err := a.s.db.GetPostForUser(r.Context(),
+		database.GetPostForUserParams{ID: postID, UserID: user.ID},
+	)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, toAPIPost(post.Post, post.FeedName))
+}
+
+func (a *api) handleMarkRead(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, ok := pathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	err := a.s.db.MarkPostRead(r.Context(),
+		database.MarkPostReadParams{UserID: user.ID, PostID: postID, ReadAt: time.Now()},
+	)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleMarkUnread(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, ok := pathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	err := a.s.db.MarkPostUnread(r.Context(),
+		database.MarkPostUnreadParams{UserID: user.ID, PostID: postID},
+	)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleStar(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, ok := pathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	err := a.s.db.StarPost(r.Context(),
+		database.StarPostParams{UserID: user.ID, PostID: postID, StarredAt: time.Now()},
+	)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) handleUnstar(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, ok := pathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	err := a.s.db.UnstarPost(r.Context(),
+		database.UnstarPostParams{UserID: user.ID, PostID: postID},
+	)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSearch takes the search command's syntax in q. Matches in the
+// headlines are wrapped in <mark> tags, everything else is plain text.
+func (a *api) handleSearch(w http.ResponseWriter, r *http.Request, user database.User) {
+	tsQuery, err := toTSQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		log.Printf("api: bad search query: %v", err)
+		respondError(w, http.StatusBadRequest, "invalid search query")
+		return
+	}
+	limit, ok := queryInt(w, r, "limit", 20, maxPageSize)
+	if !ok {
+		return
+	}
+
+	results, err := a.s.db.SearchPostsForUser(r.Context(),
+		database.SearchPostsForUserParams{Query: tsQuery, UserID: user.ID, Limit: int32(limit)},
+	)
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+
+	p := bluemonday.StrictPolicy()
+	marks := strings.NewReplacer("\x01", "<mark>", "\x02", "</mark>")
+	out := make([]apiSearchResult, len(results))
+	for i, result := range results {
+		out[i] = apiSearchResult{
+			apiPost:             toAPIPost(result.Post, result.FeedName),
+			Rank:                result.Rank,
+			TitleHeadline:       marks.Replace(p.Sanitize(result.TitleHeadline)),
+			DescriptionHeadline: marks.Replace(p.Sanitize(result.DescriptionHeadline)),
+		}
+	}
+	respondJSON(w, http.StatusOK, out)
+}
+
+func pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid "+name)
+		return uuid.UUID{}, false
+	}
+	return id, true
+}
+
+// maxPageSize is the most posts a single API request can ask for.
+const maxPageSize = 200
+
+// queryInt reads a positive integer query parameter, or def when it is
+// missing. Values over maxValue are rejected unless maxValue is 0.
+func queryInt(w http.ResponseWriter, r *http.Request, name string, def, maxValue int) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		respondError(w, http.StatusBadRequest, name+" must be a positive integer")
+		return 0, false
+	}
+	if maxValue > 0 && n > maxValue {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("%s must be at most %d", name, maxValue))
+		return 0, false
+	}
+	return n, true
+}
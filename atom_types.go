@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    AtomText    `xml:"title"`
+	Subtitle AtomText    `xml:"subtitle"`
+	Links    []AtomLink  `xml:"link"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+type AtomEntry struct {
+	ID        string       `xml:"id"`
+	Title     AtomText     `xml:"title"`
+	Links     []AtomLink   `xml:"link"`
+	Summary   AtomText     `xml:"summary"`
+	Content   AtomText     `xml:"content"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Authors   []AtomAuthor `xml:"author"`
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// AtomText holds an Atom text construct. xhtml content is kept as markup,
+// text and html content are kept as their decoded character data.
+type AtomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t AtomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// alternateLink returns the href of the rel="alternate" link, which is also
+// the default when rel is missing.
+func alternateLink(links []AtomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+func (f *AtomFeed) toRSSFeed() *RSSFeed {
+	var rss RSSFeed
+	rss.Channel.Title = f.Title.String()
+	rss.Channel.Link = alternateLink(f.Links)
+	rss.Channel.Description = f.Subtitle.String()
+
+	for _, entry := range f.Entries {
+		description := entry.Summary.String()
+		if description == "" {
+			description = entry.Content.String()
+		}
+		link := alternateLink(entry.Links)
+		if link == "" {
+			link = strings.TrimSpace(entry.ID)
+		}
+		pubDate := entry.Published
+		if pubDate == "" {
+			pubDate = entry.Updated
+		}
+		var names []string
+		for _, author := range entry.Authors {
+			if name := strings.TrimSpace(author.Name); name != "" {
+				names = append(names, name)
+			}
+		}
+		rss.Channel.Item = append(rss.Channel.Item, RSSItem{
+			Title:       entry.Title.String(),
+			Link:        link,
+			Description: description,
+			PubDate:     strings.TrimSpace(pubDate),
+			GUID:        strings.TrimSpace(entry.ID),
+			Author:      strings.Join(names, ", "),
+		})
+	}
+
+	return &rss
+}
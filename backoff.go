@@ -0,0 +1,24 @@
+package main
+
+import "time"
+
+const (
+	backoffBase = 5 * time.Minute
+	backoffMax  = 24 * time.Hour
+)
+
+// backoffDelay is how long a feed rests after failing failures times in a
+// row: 5m, 10m, 20m and so on, doubling up to a day.
+func backoffDelay(failures int32) time.Duration {
+	if failures < 1 {
+		return 0
+	}
+	delay := backoffBase
+	for i := int32(1); i < failures; i++ {
+		delay *= 2
+		if delay >= backoffMax {
+			return backoffMax
+		}
+	}
+	return delay
+}
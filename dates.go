@@ -0,0 +1,159 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// pubDateLayouts are tried in order by parsePubDate. Day-of-month uses "2"
+// rather than "02" so single digit days parse too.
+var pubDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 -07:00",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"Mon, 2 Jan 06 15:04:05 -0700",
+	"Mon, 2 Jan 06 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -07:00",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04 MST",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04:05 MST",
+	"Monday, 2 January 2006 15:04:05 -0700",
+	"Monday, 2 January 2006 15:04:05 MST",
+	"Monday, 02-Jan-06 15:04:05 MST",
+	"Mon, January 2, 2006 15:04:05 MST",
+	"January 2, 2006 15:04:05 MST",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	time.RubyDate,
+	time.UnixDate,
+	time.ANSIC,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// zoneOffsets covers the RFC 822 zone names plus the abbreviations that show
+// up most in the wild. time.Parse only knows the local zone's abbreviations
+// and silently treats every other name as UTC.
+var zoneOffsets = map[string]int{
+	"UT":   0,
+	"UTC":  0,
+	"GMT":  0,
+	"Z":    0,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+	"CST":  -6 * 3600,
+	"CDT":  -5 * 3600,
+	"MST":  -7 * 3600,
+	"MDT":  -6 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+	"AKST": -9 * 3600,
+	"AKDT": -8 * 3600,
+	"HST":  -10 * 3600,
+	"AST":  -4 * 3600,
+	"ADT":  -3 * 3600,
+	"NST":  -(3*3600 + 30*60),
+	"NDT":  -(2*3600 + 30*60),
+	"BST":  1 * 3600,
+	"IST":  5*3600 + 30*60,
+	"WET":  0,
+	"WEST": 1 * 3600,
+	"CET":  1 * 3600,
+	"CEST": 2 * 3600,
+	"MET":  1 * 3600,
+	"MEST": 2 * 3600,
+	"EET":  2 * 3600,
+	"EEST": 3 * 3600,
+	"MSK":  3 * 3600,
+	"JST":  9 * 3600,
+	"KST":  9 * 3600,
+	"HKT":  8 * 3600,
+	"SGT":  8 * 3600,
+	"AWST": 8 * 3600,
+	"ACST": 9*3600 + 30*60,
+	"ACDT": 10*3600 + 30*60,
+	"AEST": 10 * 3600,
+	"AEDT": 11 * 3600,
+	"NZST": 12 * 3600,
+	"NZDT": 13 * 3600,
+}
+
+var (
+	whitespaceRe      = regexp.MustCompile(`\s+`)
+	trailingCommentRe = regexp.MustCompile(`\s*\([^)]*\)\s*$`)
+	gmtOffsetRe       = regexp.MustCompile(`\b(?:GMT|UTC)([+-]\d{2}):?(\d{2})$`)
+)
+
+// dateReplacer fixes long or non-standard month and day names that
+// time.Parse does not accept as abbreviations.
+var dateReplacer = strings.NewReplacer(
+	"Sept ", "Sep ",
+	"Tues,", "Tue,",
+	"Wednes,", "Wed,",
+	"Thur,", "Thu,",
+	"Thurs,", "Thu,",
+)
+
+// parsePubDate parses the publication dates found in feeds, which are
+// nominally RFC 822 or RFC 3339 but frequently are neither. ok is false when
+// no layout matched.
+func parsePubDate(raw string) (time.Time, bool) {
+	value := normalizePubDate(raw)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range pubDateLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		return fixZone(t), true
+	}
+
+	return time.Time{}, false
+}
+
+func normalizePubDate(raw string) string {
+	value := strings.TrimSpace(raw)
+	value = trailingCommentRe.ReplaceAllString(value, "")
+	value = strings.ReplaceAll(value, ",", ", ")
+	value = whitespaceRe.ReplaceAllString(value, " ")
+	value = strings.ReplaceAll(value, " ,", ",")
+	value = dateReplacer.Replace(value)
+	// "GMT+0200" and "UTC-05:00" become plain numeric offsets.
+	value = gmtOffsetRe.ReplaceAllString(value, "$1$2")
+	return strings.TrimSpace(value)
+}
+
+// fixZone replaces the zero offset time.Parse gives unknown zone
+// abbreviations with the real one, when we know it.
+func fixZone(t time.Time) time.Time {
+	name, offset := t.Zone()
+	if offset != 0 {
+		return t
+	}
+	known, ok := zoneOffsets[strings.ToUpper(name)]
+	if !ok || known == 0 {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(),
+		t.Nanosecond(), time.FixedZone(name, known))
+}
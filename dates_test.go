@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePubDate(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want time.Time
+		ok   bool
+	}{
+		{"rfc1123z", "Mon, 02 Jan 2006 15:04:05 -0700", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC), true},
+		{"single digit day", "Tue, 5 Mar 2024 10:00:00 +0000", time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC), true},
+		{"rfc3339", "2024-03-05T10:00:00Z", time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC), true},
+		{"rfc3339 offset", "2024-03-05T10:00:00+02:00", time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC), true},
+		{"named zone", "Tue, 5 Mar 2024 10:00:00 EST", time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC), true},
+		{"gmt offset", "Tue, 5 Mar 2024 10:00:00 GMT+0200", time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC), true},
+		{"long day name", "Thurs, 7 Mar 2024 10:00:00 GMT", time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC), true},
+		{"trailing comment", "Mon, 4 Mar 2024 10:00:00 +0000 (UTC)", time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC), true},
+		{"extra spaces", "  Mon,  4 Mar 2024   10:00:00 +0000 ", time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC), true},
+		{"date only", "2024-03-04", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), true},
+		{"empty", "", time.Time{}, false},
+		{"garbage", "last tuesday", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parsePubDate(tt.raw)
+			if ok != tt.ok {
+				t.Fatalf("parsePubDate(%q) ok = %v, want %v", tt.raw, ok, tt.ok)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parsePubDate(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
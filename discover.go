@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// feedLinkTypes are the <link rel="alternate"> types that point at a feed.
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	"application/rdf+xml":   true,
+}
+
+// fallbackFeedPaths are tried against the site root when a page does not
+// advertise any feed.
+var fallbackFeedPaths = []string{
+	"/feed",
+	"/rss.xml",
+	"/atom.xml",
+	"/feed.xml",
+	"/index.xml",
+	"/rss",
+}
+
+// discoverFeeds returns the feed URLs for pageURL. A URL that already is a
+// feed comes back on its own; for an HTML page it is every feed the page
+// advertises, or else the first common feed path that works. When discovery
+// had to download the first feed it is returned parsed, otherwise it is nil.
+func discoverFeeds(ctx context.Context, pageURL string) ([]string, *RSSFeed, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", "gator")
+
+	res, err := feedClient(ctx).Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, nil, &httpStatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	data, err := readBody(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Follow redirects so relative links resolve against the final page.
+	base := res.Request.URL
+
+	if rss, err := parseFeed(res.Header.Get("Content-Type"), data); err == nil {
+		return []string{base.String()}, unescapeFeed(rss), nil
+	}
+
+	feeds, err := feedLinks(base, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(feeds) > 0 {
+		return feeds, nil, nil
+	}
+
+	for _, path := range fallbackFeedPaths {
+		candidate := base.ResolveReference(&url.URL{Path: path}).String()
+		if rss, err := fetchFeed(ctx, candidate); err == nil {
+			return []string{candidate}, rss, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("no feed found at %s", pageURL)
+}
+
+// discovery is the outcome of resolveFeedURL.
+type discovery struct {
+	// URL is the feed picked, Others the rest the page advertises.
+	URL    string
+	Others []string
+	// Feed is URL parsed, when discovery already downloaded it.
+	Feed *RSSFeed
+}
+
+// resolveFeedURL runs discovery on rawURL and picks the first feed found,
+// returning any others so the user can add one of those instead.
+func resolveFeedURL(ctx context.Context, rawURL string) (discovery, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	feeds, rss, err := discoverFeeds(ctx, rawURL)
+	if err != nil {
+		return discovery{}, err
+	}
+	return discovery{URL: feeds[0], Others: feeds[1:], Feed: rss}, nil
+}
+
+// feedLinks parses an HTML page and returns the absolute URLs of its
+// <link rel="alternate"> feeds, honouring <base href>.
+func feedLinks(base *url.URL, page []byte) ([]string, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	var hrefs []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "base":
+				if href := htmlAttr(n, "href"); href != "" {
+					if u, err := base.Parse(href); err == nil {
+						base = u
+					}
+				}
+			case "link":
+				rels := strings.Fields(strings.ToLower(htmlAttr(n, "rel")))
+				linkType := strings.ToLower(strings.TrimSpace(htmlAttr(n, "type")))
+				if containsString(rels, "alternate") && feedLinkTypes[linkType] {
+					if href := htmlAttr(n, "href"); href != "" {
+						hrefs = append(hrefs, href)
+					}
+				}
+			case "body":
+				// Feed links belong in <head>, skip the rest of the page.
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var feeds []string
+	seen := make(map[string]bool)
+	for _, href := range hrefs {
+		u, err := base.Parse(href)
+		if err != nil || seen[u.String()] {
+			continue
+		}
+		seen[u.String()] = true
+		feeds = append(feeds, u.String())
+	}
+	return feeds, nil
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFeedLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want []string
+	}{
+		{
+			"relative and absolute",
+			`<html><head>
+			<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+			<link rel="alternate" type="application/atom+xml" href="https://cdn.example/atom.xml">
+			</head></html>`,
+			[]string{"https://example.com/feed.xml", "https://cdn.example/atom.xml"},
+		},
+		{
+			"base href",
+			`<html><head><base href="https://example.com/blog/">
+			<link rel="alternate" type="application/feed+json" href="feed.json">
+			</head></html>`,
+			[]string{"https://example.com/blog/feed.json"},
+		},
+		{
+			"rel and type are case insensitive",
+			`<head><link rel="Alternate Home" type=" Application/RDF+XML " href="index.rdf"></head>`,
+			[]string{"https://example.com/posts/index.rdf"},
+		},
+		{
+			"other links are ignored",
+			`<head>
+			<link rel="stylesheet" type="text/css" href="/style.css">
+			<link rel="alternate" type="text/html" hreflang="fr" href="/fr/">
+			<link rel="alternate" type="application/rss+xml">
+			</head>`,
+			nil,
+		},
+		{
+			"duplicates are dropped",
+			`<head>
+			<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+			<link rel="alternate" type="application/rss+xml" href="https://example.com/feed.xml">
+			</head>`,
+			[]string{"https://example.com/feed.xml"},
+		},
+		{
+			"links in the body are ignored",
+			`<head></head><body><link rel="alternate" type="application/rss+xml" href="/feed.xml"></body>`,
+			nil,
+		},
+	}
+	base, err := url.Parse("https://example.com/posts/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := feedLinks(base, []byte(tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("feedLinks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+// feverAPI implements the Fever API at /fever/. Clients authenticate with an
+// api_key of md5("<user>:<password>"), which passwd stores next to the
+// password hash, and ask for data with empty query parameters such as
+// ?api&items&since_id=10.
+//
+// Fever only knows integer ids. Items use posts.item_id, feeds use
+// feeds.numeric_id, and groups, which are follow categories here, are
+// numbered by a hash of their name.
+type feverAPI struct {
+	s *state
+}
+
+func (f *feverAPI) register(mux *http.ServeMux) {
+	mux.HandleFunc("/fever", f.handle)
+	mux.HandleFunc("/fever/{$}", f.handle)
+}
+
+// feverAPIKey is what Fever clients send in place of a password. Fever's
+// scheme uses the email address, gator has only user names. Only its
+// hashAPIToken is stored.
+func feverAPIKey(name, password string) string {
+	sum := md5.Sum([]byte(name + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// feverGroupID numbers a category. 0 means every feed in Fever, so it is
+// never handed out.
+func feverGroupID(category string) int64 {
+	id := int64(crc32.ChecksumIEEE([]byte(category)) & 0x7fffffff)
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+type feverGroup struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type feverFeedsGroup struct {
+	GroupID int64  `json:"group_id"`
+	FeedIDs string `json:"feed_ids"`
+}
+
+type feverFeed struct {
+	ID                int64  `json:"id"`
+	FaviconID         int64  `json:"favicon_id"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	SiteURL           string `json:"site_url"`
+	IsSpark           int    `json:"is_spark"`
+	LastUpdatedOnTime int64  `json:"last_updated_on_time"`
+}
+
+type feverItem struct {
+	ID            int64  `json:"id"`
+	FeedID        int64  `json:"feed_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	HTML          string `json:"html"`
+	URL           string `json:"url"`
+	IsSaved       int    `json:"is_saved"`
+	IsRead        int    `json:"is_read"`
+	CreatedOnTime int64  `json:"created_on_time"`
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *feverAPI) handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp := map[string]any{"api_version": 3, "auth": 0}
+
+	key := strings.ToLower(r.Form.Get("api_key"))
+	user, err := f.s.db.GetUserByFeverApiKey(r.Context(), sql.NullString{String: hashAPIToken(key), Valid: key != ""})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Fever answers a bad key with auth 0 rather than an HTTP error.
+		respondJSON(w, http.StatusOK, resp)
+		return
+	}
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	resp["auth"] = 1
+
+	status, err := f.s.db.GetAggStatus(r.Context())
+	if err != nil {
+		respondServerError(w, err)
+		return
+	}
+	resp["last_refreshed_on_time"] = int64(0)
+	if status.LastFetchedAt.Valid {
+		resp["last_refreshed_on_time"] = status.LastFetchedAt.Time.Unix()
+	}
+
+	// Marks go first so the lists below already reflect them.
+	if r.Form.Has("mark") {
+		if err := f.mark(r, user); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if err := f.addLists(r, user, resp); err != nil {
+		respondServerError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (f *feverAPI) addLists(r *http.Request, user database.User, resp map[string]any) error {
+	ctx := r.Context()
+	if r.Form.Has("groups") || r.Form.Has("feeds") {
+		feeds, err := f.s.db.GetFeverFeeds(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		var groups []feverGroup
+		groupFeeds := map[int64][]int64{}
+		for _, feed := range feeds {
+			if !feed.Category.Valid {
+				continue
+			}
+			id := feverGroupID(feed.Category.String)
+			if _, ok := groupFeeds[id]; !ok {
+				groups = append(groups, feverGroup{ID: id, Title: feed.Category.String})
+			}
+			groupFeeds[id] = append(groupFeeds[id], feed.NumericID)
+		}
+		feedsGroups := make([]feverFeedsGroup, len(groups))
+		for i, group := range groups {
+			feedsGroups[i] = feverFeedsGroup{GroupID: group.ID, FeedIDs: joinIDs(groupFeeds[group.ID])}
+		}
+
+		if r.Form.Has("groups") {
+			if groups == nil {
+				groups = []feverGroup{}
+			}
+			resp["groups"] = groups
+		}
+		if r.Form.Has("feeds") {
+			out := make([]feverFeed, len(feeds))
+			for i, feed := range feeds {
+				out[i] = feverFeed{
+					ID:      feed.NumericID,
+					Title:   feed.Name,
+					URL:     feed.Url,
+					SiteURL: feed.Link.String,
+				}
+				if feed.LastFetchedAt.Valid {
+					out[i].LastUpdatedOnTime = feed.LastFetchedAt.Time.Unix()
+				}
+			}
+			resp["feeds"] = out
+		}
+		resp["feeds_groups"] = feedsGroups
+	}
+
+	if r.Form.Has("favicons") {
+		resp["favicons"] = []any{}
+	}
+	if r.Form.Has("links") {
+		resp["links"] = []any{}
+	}
+
+	if r.Form.Has("items") {
+		items, err := f.s.db.GetFeverItems(ctx, feverItemsParams(r, user))
+		if err != nil {
+			return err
+		}
+		out := make([]feverItem, len(items))
+		for i, item := range items {
+			out[i] = feverItem{
+				ID:            item.ItemID,
+				FeedID:        item.FeedNumericID,
+				Title:         item.Title.String,
+				Author:        item.Author.String,
+				HTML:          item.Description.String,
+				URL:           item.Url,
+				IsSaved:       boolInt(item.IsSaved),
+				IsRead:        boolInt(item.IsRead),
+				CreatedOnTime: item.PublishedAt.Unix(),
+			}
+		}
+		total, err := f.s.db.GetFeverItemCount(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		resp["items"] = out
+		resp["total_items"] = total
+	}
+
+	if r.Form.Has("unread_item_ids") {
+		ids, err := f.s.db.GetFeverUnreadItemIds(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		resp["unread_item_ids"] = joinIDs(ids)
+	}
+	if r.Form.Has("saved_item_ids") {
+		ids, err := f.s.db.GetFeverSavedItemIds(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		resp["saved_item_ids"] = joinIDs(ids)
+	}
+	return nil
+}
+
+// feverItemsParams reads since_id, max_id and the comma separated with_ids.
+// Malformed values are ignored as Fever does.
+func feverItemsParams(r *http.Request, user database.User) database.GetFeverItemsParams {
+	params := database.GetFeverItemsParams{UserID: user.ID, ItemIds: []int64{}}
+	if id, err := strconv.ParseInt(r.Form.Get("since_id"), 10, 64); err == nil {
+		params.SinceID = sql.NullInt64{Int64: id, Valid: true}
+	}
+	if id, err := strconv.ParseInt(r.Form.Get("max_id"), 10, 64); err == nil && id > 0 {
+		params.MaxID = sql.NullInt64{Int64: id, Valid: true}
+	}
+	if withIDs := r.Form.Get("with_ids"); withIDs != "" {
+		for _, raw := range strings.Split(withIDs, ",") {
+			if id, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64); err == nil {
+				params.ItemIds = append(params.ItemIds, id)
+			}
+		}
+	}
+	return params
+}
+
+// mark handles mark=item with as=read, unread, saved or unsaved, and
+// mark=feed or mark=group with as=read, limited to posts published before
+// the before timestamp.
+func (f *feverAPI) mark(r *http.Request, user database.User) error {
+	ctx := r.Context()
+	id, err := strconv.ParseInt(r.Form.Get("id"), 10, 64)
+	if err != nil {
+		return errors.New("mark needs a numeric id")
+	}
+	as := r.Form.Get("as")
+
+	if r.Form.Get("mark") == "item" {
+		posts, err := f.s.db.GetGReaderItems(ctx,
+			database.GetGReaderItemsParams{UserID: user.ID, ItemIds: []int64{id}},
+		)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return nil
+		}
+		postID := posts[0].Post.ID
+		switch as {
+		case "read":
+			return f.s.db.MarkPostRead(ctx,
+				database.MarkPostReadParams{UserID: user.ID, PostID: postID, ReadAt: time.Now()},
+			)
+		case "unread":
+			return f.s.db.MarkPostUnread(ctx,
+				database.MarkPostUnreadParams{UserID: user.ID, PostID: postID},
+			)
+		case "saved":
+			return f.s.db.StarPost(ctx,
+				database.StarPostParams{UserID: user.ID, PostID: postID, StarredAt: time.Now()},
+			)
+		case "unsaved":
+			return f.s.db.UnstarPost(ctx,
+				database.UnstarPostParams{UserID: user.ID, PostID: postID},
+			)
+		}
+		return errors.New("as must be read, unread, saved or unsaved")
+	}
+
+	if as != "read" {
+		return errors.New("feeds and groups can only be marked read")
+	}
+	params := database.MarkAllPostsReadParams{ReadAt: time.Now(), UserID: user.ID}
+	if before, err := strconv.ParseInt(r.Form.Get("before"), 10, 64); err == nil && before > 0 {
+		params.PublishedBefore = sql.NullTime{Time: time.Unix(before, 0), Valid: true}
+	}
+
+	feeds, err := f.s.db.GetFeverFeeds(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	switch r.Form.Get("mark") {
+	case "feed":
+		for _, feed := range feeds {
+			if feed.NumericID == id {
+				params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+			}
+		}
+		if !params.FeedID.Valid {
+			return nil
+		}
+	case "group":
+		// Group 0 is everything, any other unknown group is nothing.
+		if id != 0 {
+			for _, feed := range feeds {
+				if feed.Category.Valid && feverGroupID(feed.Category.String) == id {
+					params.Category = feed.Category
+				}
+			}
+			if !params.Category.Valid {
+				return nil
+			}
+		}
+	default:
+		return errors.New("mark must be item, feed or group")
+	}
+	_, err = f.s.db.MarkAllPostsRead(ctx, params)
+	return err
+}
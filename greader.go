@@ -0,0 +1,619 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+// greaderAPI speaks enough of the Google Reader API, as implemented by
+// FreshRSS and Miniflux, for Reeder, NetNewsWire and friends. Clients are
+// pointed at http://host:port/api/greader and log in with the user name and
+// the password set by the passwd command.
+//
+// Feeds are streams named feed/<url>, a follow's category is the label
+// user/-/label/<category>, and read and starred map onto post_reads and
+// post_stars.
+type greaderAPI struct {
+	s *state
+}
+
+const greaderPrefix = "/api/greader"
+
+const (
+	greaderReadingList = "user/-/state/com.google/reading-list"
+	greaderRead        = "user/-/state/com.google/read"
+	greaderStarred     = "user/-/state/com.google/starred"
+	greaderKeptUnread  = "user/-/state/com.google/kept-unread"
+	greaderLabelPrefix = "user/-/label/"
+	greaderFeedPrefix  = "feed/"
+	greaderItemPrefix  = "tag:google.com,2005:reader/item/"
+)
+
+func (g *greaderAPI) register(mux *http.ServeMux) {
+	api := greaderPrefix + "/reader/api/0"
+	mux.HandleFunc("POST "+greaderPrefix+"/accounts/ClientLogin", g.handleClientLogin)
+	mux.HandleFunc("GET "+api+"/token", g.authenticated(g.handleToken))
+	mux.HandleFunc("GET "+api+"/user-info", g.authenticated(g.handleUserInfo))
+	mux.HandleFunc("GET "+api+"/subscription/list", g.authenticated(g.handleSubscriptionList))
+	mux.HandleFunc("GET "+api+"/tag/list", g.authenticated(g.handleTagList))
+	mux.HandleFunc("GET "+api+"/unread-count", g.authenticated(g.handleUnreadCount))
+	mux.HandleFunc(api+"/stream/items/ids", g.authenticated(g.handleStreamItemIDs))
+	mux.HandleFunc(api+"/stream/contents", g.authenticated(g.handleStreamContents))
+	mux.HandleFunc(api+"/stream/contents/{stream...}", g.authenticated(g.handleStreamContents))
+	mux.HandleFunc(api+"/stream/items/contents", g.authenticated(g.handleStreamItemContents))
+	mux.HandleFunc("POST "+api+"/edit-tag", g.authenticated(g.handleEditTag))
+	mux.HandleFunc("POST "+api+"/mark-all-as-read", g.authenticated(g.handleMarkAllAsRead))
+}
+
+func (g *greaderAPI) handleClientLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Error=BadRequest", http.StatusBadRequest)
+		return
+	}
+	// Credentials only count from the body, never from the query string.
+	name := r.PostForm.Get("Email")
+	user, err := g.s.db.GetUserWithPassword(r.Context(), name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("greader error: %v", err)
+		http.Error(w, "Error=Unknown", http.StatusInternalServerError)
+		return
+	}
+	if err != nil || !checkPassword(r.PostForm.Get("Passwd"), user.PasswordHash) {
+		http.Error(w, "Error=BadAuthentication", http.StatusUnauthorized)
+		return
+	}
+
+	token := passwordToken("greader", user.Name, user.PasswordHash)
+	if r.Form.Get("output") == "json" {
+		respondJSON(w, http.StatusOK, map[string]string{"SID": token, "LSID": "null", "Auth": token})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "SID=%s\nLSID=null\nAuth=%s\n", token, token)
+}
+
+// authenticated checks the "Authorization: GoogleLogin auth=<token>" header
+// every call after ClientLogin carries.
+func (g *greaderAPI) authenticated(handler func(w http.ResponseWriter, r *http.Request, user database.User)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "GoogleLogin auth=")
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := userFromPasswordToken(r.Context(), g.s, "greader", token)
+		if errors.Is(err, errBadToken) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			greaderError(w, err)
+			return
+		}
+
+		handler(w, r, user)
+	}
+}
+
+func greaderError(w http.ResponseWriter, err error) {
+	log.Printf("greader error: %v", err)
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+}
+
+func greaderOK(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, "OK")
+}
+
+// handleToken hands out the T parameter clients send with every edit. The
+// API is authorized by header rather than cookie, so T guards against
+// nothing and is not checked.
+func (g *greaderAPI) handleToken(w http.ResponseWriter, r *http.Request, user database.User) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, strings.ReplaceAll(user.ID.String(), "-", ""))
+}
+
+func (g *greaderAPI) handleUserInfo(w http.ResponseWriter, r *http.Request, user database.User) {
+	respondJSON(w, http.StatusOK, map[string]string{
+		"userId":        user.ID.String(),
+		"userName":      user.Name,
+		"userProfileId": user.ID.String(),
+		"userEmail":     "",
+	})
+}
+
+type greaderCategory struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+type greaderSubscription struct {
+	ID         string            `json:"id"`
+	Title      string            `json:"title"`
+	Categories []greaderCategory `json:"categories"`
+	URL        string            `json:"url"`
+	HTMLURL    string            `json:"htmlUrl"`
+	IconURL    string            `json:"iconUrl"`
+}
+
+func (g *greaderAPI) handleSubscriptionList(w http.ResponseWriter, r *http.Request, user database.User) {
+	follows, err := g.s.db.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		greaderError(w, err)
+		return
+	}
+	subscriptions := make([]greaderSubscription, len(follows))
+	for i, follow := range follows {
+		categories := []greaderCategory{}
+		if follow.Category.Valid {
+			categories = append(categories, greaderCategory{
+				ID:    greaderLabelPrefix + follow.Category.String,
+				Label: follow.Category.String,
+			})
+		}
+		subscriptions[i] = greaderSubscription{
+			ID:         greaderFeedPrefix + follow.FeedUrl,
+			Title:      follow.FeedName,
+			Categories: categories,
+			URL:        follow.FeedUrl,
+			HTMLURL:    follow.FeedLink.String,
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"subscriptions": subscriptions})
+}
+
+type greaderTag struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+func (g *greaderAPI) handleTagList(w http.ResponseWriter, r *http.Request, user database.User) {
+	follows, err := g.s.db.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		greaderError(w, err)
+		return
+	}
+	tags := []greaderTag{{ID: greaderStarred}}
+	seen := map[string]bool{}
+	for _, follow := range follows {
+		if follow.Category.Valid && !seen[follow.Category.String] {
+			seen[follow.Category.String] = true
+			tags = append(tags, greaderTag{ID: greaderLabelPrefix + follow.Category.String, Type: "folder"})
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"tags": tags})
+}
+
+type greaderUnreadCount struct {
+	ID                      string `json:"id"`
+	Count                   int64  `json:"count"`
+	NewestItemTimestampUsec string `json:"newestItemTimestampUsec"`
+}
+
+// handleUnreadCount reports every followed feed, every label and the reading
+// list as a whole.
+func (g *greaderAPI) handleUnreadCount(w http.ResponseWriter, r *http.Request, user database.User) {
+	follows, err := g.s.db.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		greaderError(w, err)
+		return
+	}
+	var counts []greaderUnreadCount
+	labels := map[string]int64{}
+	var labelOrder []string
+	var total int64
+	for _, follow := range follows {
+		counts = append(counts, greaderUnreadCount{
+			ID:                      greaderFeedPrefix + follow.FeedUrl,
+			Count:                   follow.UnreadCount,
+			NewestItemTimestampUsec: "0",
+		})
+		if follow.Category.Valid {
+			if _, ok := labels[follow.Category.String]; !ok {
+				labelOrder = append(labelOrder, follow.Category.String)
+			}
+			labels[follow.Category.String] += follow.UnreadCount
+		}
+		total += follow.UnreadCount
+	}
+	for _, label := range labelOrder {
+		counts = append(counts, greaderUnreadCount{
+			ID:                      greaderLabelPrefix + label,
+			Count:                   labels[label],
+			NewestItemTimestampUsec: "0",
+		})
+	}
+	counts = append(counts, greaderUnreadCount{
+		ID:                      greaderReadingList,
+		Count:                   total,
+		NewestItemTimestampUsec: "0",
+	})
+	respondJSON(w, http.StatusOK, map[string]any{"max": total, "unreadcounts": counts})
+}
+
+// greaderUserPart matches the user id in a stream or tag. Clients may send
+// their numeric id instead of "-", which always means the caller here.
+var greaderUserPart = regexp.MustCompile(`^user/[^/]+/`)
+
+func normalizeGReaderTag(tag string) string {
+	return greaderUserPart.ReplaceAllString(tag, "user/-/")
+}
+
+// greaderStreamParams turns the stream id s and the it, xt, n, r, ot, nt and
+// c parameters of a stream request into the query's parameters.
+func (g *greaderAPI) greaderStreamParams(r *http.Request, user database.User, stream string, maxItems int) (database.GetGReaderStreamParams, error) {
+	params := database.GetGReaderStreamParams{UserID: user.ID, Limit: 20}
+	if err := g.applyGReaderStream(r, &params, stream); err != nil {
+		return params, err
+	}
+	for _, target := range r.Form["it"] {
+		if err := g.applyGReaderStream(r, &params, target); err != nil {
+			return params, err
+		}
+	}
+	for _, target := range r.Form["xt"] {
+		switch normalizeGReaderTag(target) {
+		case greaderRead:
+			params.Read = sql.NullBool{Bool: false, Valid: true}
+		case greaderKeptUnread:
+			params.Read = sql.NullBool{Bool: true, Valid: true}
+		}
+	}
+
+	if n := r.Form.Get("n"); n != "" {
+		count, err := strconv.Atoi(n)
+		if err != nil || count < 1 {
+			return params, fmt.Errorf("invalid n: %s", n)
+		}
+		params.Limit = int32(min(count, maxItems))
+	}
+	if c := r.Form.Get("c"); c != "" {
+		offset, err := strconv.Atoi(c)
+		if err != nil || offset < 0 {
+			return params, fmt.Errorf("invalid continuation: %s", c)
+		}
+		params.Offset = int32(offset)
+	}
+	params.OldestFirst = r.Form.Get("r") == "o"
+	if ot := r.Form.Get("ot"); ot != "" {
+		secs, err := strconv.ParseInt(ot, 10, 64)
+		if err != nil {
+			return params, fmt.Errorf("invalid ot: %s", ot)
+		}
+		params.Since = sql.NullTime{Time: time.Unix(secs, 0), Valid: true}
+	}
+	if nt := r.Form.Get("nt"); nt != "" {
+		secs, err := strconv.ParseInt(nt, 10, 64)
+		if err != nil {
+			return params, fmt.Errorf("invalid nt: %s", nt)
+		}
+		params.Until = sql.NullTime{Time: time.Unix(secs, 0), Valid: true}
+	}
+	return params, nil
+}
+
+func (g *greaderAPI) applyGReaderStream(r *http.Request, params *database.GetGReaderStreamParams, stream string) error {
+	stream = normalizeGReaderTag(stream)
+	switch {
+	case stream == "" || stream == greaderReadingList:
+	case stream == greaderRead:
+		params.Read = sql.NullBool{Bool: true, Valid: true}
+	case stream == greaderKeptUnread:
+		params.Read = sql.NullBool{Bool: false, Valid: true}
+	case stream == greaderStarred:
+		params.StarredOnly = true
+	case strings.HasPrefix(stream, greaderLabelPrefix):
+		params.Category = sql.NullString{String: strings.TrimPrefix(stream, greaderLabelPrefix), Valid: true}
+	case strings.HasPrefix(stream, greaderFeedPrefix):
+		feed, err := g.s.db.GetFeedsByUrl(r.Context(), strings.TrimPrefix(stream, greaderFeedPrefix))
+		if err != nil {
+			return fmt.Errorf("unknown stream %s: %w", stream, err)
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	default:
+		return fmt.Errorf("unknown stream %s", stream)
+	}
+	return nil
+}
+
+func greaderContinuation(params database.GetGReaderStreamParams, got int) string {
+	if got < int(params.Limit) {
+		return ""
+	}
+	return strconv.Itoa(int(params.Offset) + got)
+}
+
+type greaderItemRef struct {
+	ID              string   `json:"id"`
+	DirectStreamIDs []string `json:"directStreamIds"`
+	TimestampUsec   string   `json:"timestampUsec"`
+}
+
+func (g *greaderAPI) handleStreamItemIDs(w http.ResponseWriter, r *http.Request, user database.User) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params, err := g.greaderStreamParams(r, user, r.Form.Get("s"), 10000)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	posts, err := g.s.db.GetGReaderStream(r.Context(), params)
+	if err != nil {
+		greaderError(w, err)
+		return
+	}
+
+	refs := make([]greaderItemRef, len(posts))
+	for i, post := range posts {
+		refs[i] = greaderItemRef{
+			ID:              strconv.FormatInt(post.Post.ItemID, 10),
+			DirectStreamIDs: []string{},
+			TimestampUsec:   strconv.FormatInt(post.Post.PublishedAt.UnixMicro(), 10),
+		}
+	}
+	body := map[string]any{"itemRefs": refs}
+	if c := greaderContinuation(params, len(posts)); c != "" {
+		body["continuation"] = c
+	}
+	respondJSON(w, http.StatusOK, body)
+}
+
+// handleStreamContents takes the stream id either from the path, as
+// /stream/contents/feed%2Fhttps..., or from the s parameter.
+func (g *greaderAPI) handleStreamContents(w http.ResponseWriter, r *http.Request, user database.User) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stream := r.PathValue("stream")
+	if stream == "" {
+		stream = r.Form.Get("s")
+	}
+	if stream == "" {
+		stream = greaderReadingList
+	}
+	params, err := g.greaderStreamParams(r, user, stream, 1000)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	posts, err := g.s.db.GetGReaderStream(r.Context(), params)
+	if err != nil {
+		greaderError(w, err)
+		return
+	}
+
+	items := make([]greaderItem, len(posts))
+	for i, post := range posts {
+		items[i] = toGReaderItem(database.GetGReaderItemsRow(post))
+	}
+	body := map[string]any{
+		"id":      stream,
+		"updated": time.Now().Unix(),
+		"items":   items,
+	}
+	if c := greaderContinuation(params, len(posts)); c != "" {
+		body["continuation"] = c
+	}
+	respondJSON(w, http.StatusOK, body)
+}
+
+func (g *greaderAPI) handleStreamItemContents(w http.ResponseWriter, r *http.Request, user database.User) {
+	posts, ok := g.formItems(w, r, user)
+	if !ok {
+		return
+	}
+	items := make([]greaderItem, len(posts))
+	for i, post := range posts {
+		items[i] = toGReaderItem(post)
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"id":      greaderReadingList,
+		"updated": time.Now().Unix(),
+		"items":   items,
+	})
+}
+
+// handleEditTag adds the a tags to and removes the r tags from the i items.
+// Only read, kept-unread and starred mean anything to gator, labels belong to
+// feeds rather than items and are ignored.
+func (g *greaderAPI) handleEditTag(w http.ResponseWriter, r *http.Request, user database.User) {
+	posts, ok := g.formItems(w, r, user)
+	if !ok {
+		return
+	}
+
+	for _, post := range posts {
+		for _, tag := range r.Form["a"] {
+			if err := g.setGReaderTag(r, user, post.Post.ID, normalizeGReaderTag(tag), true); err != nil {
+				greaderError(w, err)
+				return
+			}
+		}
+		for _, tag := range r.Form["r"] {
+			if err := g.setGReaderTag(r, user, post.Post.ID, normalizeGReaderTag(tag), false); err != nil {
+				greaderError(w, err)
+				return
+			}
+		}
+	}
+	greaderOK(w)
+}
+
+func (g *greaderAPI) setGReaderTag(r *http.Request, user database.User, postID uuid.UUID, tag string, on bool) error {
+	if tag == greaderKeptUnread {
+		tag, on = greaderRead, !on
+	}
+	switch {
+	case tag == greaderRead && on:
+		return g.s.db.MarkPostRead(r.Context(),
+			database.MarkPostReadParams{UserID: user.ID, PostID: postID, ReadAt: time.Now()},
+		)
+	case tag == greaderRead:
+		return g.s.db.MarkPostUnread(r.Context(),
+			database.MarkPostUnreadParams{UserID: user.ID, PostID: postID},
+		)
+	case tag == greaderStarred && on:
+		return g.s.db.StarPost(r.Context(),
+			database.StarPostParams{UserID: user.ID, PostID: postID, StarredAt: time.Now()},
+		)
+	case tag == greaderStarred:
+		return g.s.db.UnstarPost(r.Context(),
+			database.UnstarPostParams{UserID: user.ID, PostID: postID},
+		)
+	}
+	return nil
+}
+
+// handleMarkAllAsRead marks the stream s read, up to the ts timestamp in
+// microseconds when one is given so items that arrived since stay unread.
+func (g *greaderAPI) handleMarkAllAsRead(w http.ResponseWriter, r *http.Request, user database.User) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var stream database.GetGReaderStreamParams
+	if err := g.applyGReaderStream(r, &stream, r.Form.Get("s")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The read and kept-unread states need nothing more: marking read posts
+	// read changes nothing, and every unread post is kept unread.
+	params := database.MarkAllPostsReadParams{
+		ReadAt:      time.Now(),
+		UserID:      user.ID,
+		FeedID:      stream.FeedID,
+		Category:    stream.Category,
+		StarredOnly: stream.StarredOnly,
+	}
+	if ts := r.Form.Get("ts"); ts != "" {
+		usec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid ts", http.StatusBadRequest)
+			return
+		}
+		params.PublishedBefore = sql.NullTime{Time: time.UnixMicro(usec), Valid: true}
+	}
+	if _, err := g.s.db.MarkAllPostsRead(r.Context(), params); err != nil {
+		greaderError(w, err)
+		return
+	}
+	greaderOK(w)
+}
+
+// formItems loads the posts named by the i parameters, skipping any the
+// user doesn't follow.
+func (g *greaderAPI) formItems(w http.ResponseWriter, r *http.Request, user database.User) ([]database.GetGReaderItemsRow, bool) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	ids := make([]int64, 0, len(r.Form["i"]))
+	for _, raw := range r.Form["i"] {
+		id, err := parseGReaderItemID(raw)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	posts, err := g.s.db.GetGReaderItems(r.Context(),
+		database.GetGReaderItemsParams{UserID: user.ID, ItemIds: ids},
+	)
+	if err != nil {
+		greaderError(w, err)
+		return nil, false
+	}
+	return posts, true
+}
+
+// parseGReaderItemID accepts both the long form, with the id in hex, and the
+// short decimal form.
+func parseGReaderItemID(raw string) (int64, error) {
+	if hexID, ok := strings.CutPrefix(raw, greaderItemPrefix); ok {
+		id, err := strconv.ParseUint(hexID, 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid item id %s", raw)
+		}
+		return int64(id), nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid item id %s", raw)
+	}
+	return id, nil
+}
+
+type greaderLink struct {
+	Href string `json:"href"`
+	Type string `json:"type,omitempty"`
+}
+
+type greaderItem struct {
+	ID            string        `json:"id"`
+	CrawlTimeMsec string        `json:"crawlTimeMsec"`
+	TimestampUsec string        `json:"timestampUsec"`
+	Published     int64         `json:"published"`
+	Updated       int64         `json:"updated"`
+	Title         string        `json:"title"`
+	Author        string        `json:"author,omitempty"`
+	Canonical     []greaderLink `json:"canonical"`
+	Alternate     []greaderLink `json:"alternate"`
+	Enclosure     []greaderLink `json:"enclosure,omitempty"`
+	Categories    []string      `json:"categories"`
+	Origin        struct {
+		StreamID string `json:"streamId"`
+		Title    string `json:"title"`
+		HTMLURL  string `json:"htmlUrl"`
+	} `json:"origin"`
+	Summary struct {
+		Content string `json:"content"`
+	} `json:"summary"`
+}
+
+func toGReaderItem(post database.GetGReaderItemsRow) greaderItem {
+	item := greaderItem{
+		ID:            fmt.Sprintf("%s%016x", greaderItemPrefix, uint64(post.Post.ItemID)),
+		CrawlTimeMsec: strconv.FormatInt(post.Post.CreatedAt.UnixMilli(), 10),
+		TimestampUsec: strconv.FormatInt(post.Post.PublishedAt.UnixMicro(), 10),
+		Published:     post.Post.PublishedAt.Unix(),
+		Updated:       post.Post.UpdatedAt.Unix(),
+		Title:         post.Post.Title.String,
+		Author:        post.Post.Author.String,
+		Canonical:     []greaderLink{{Href: post.Post.Url}},
+		Alternate:     []greaderLink{{Href: post.Post.Url, Type: "text/html"}},
+		Categories:    []string{greaderReadingList},
+	}
+	if post.Post.EnclosureUrl.Valid {
+		item.Enclosure = []greaderLink{{Href: post.Post.EnclosureUrl.String, Type: post.Post.EnclosureType.String}}
+	}
+	if post.Category.Valid {
+		item.Categories = append(item.Categories, greaderLabelPrefix+post.Category.String)
+	}
+	if post.IsRead {
+		item.Categories = append(item.Categories, greaderRead)
+	}
+	if post.IsStarred {
+		item.Categories = append(item.Categories, greaderStarred)
+	}
+	item.Origin.StreamID = greaderFeedPrefix + post.FeedUrl
+	item.Origin.Title = post.FeedName
+	item.Origin.HTMLURL = post.FeedLink.String
+	// Readers render the summary as HTML themselves.
+	item.Summary.Content = post.Post.Description.String
+	return item
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestParseGReaderItemID(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int64
+		wantErr bool
+	}{
+		{"42", 42, false},
+		{"tag:google.com,2005:reader/item/000000000000002a", 42, false},
+		{"tag:google.com,2005:reader/item/2a", 42, false},
+		{"tag:google.com,2005:reader/item/ffffffffffffffff", -1, false},
+		{"tag:google.com,2005:reader/item/xyz", 0, true},
+		{"2a", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseGReaderItemID(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseGReaderItemID(%q) error = %v, want error %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseGReaderItemID(%q) = %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeGReaderTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"user/-/state/com.google/read", greaderRead},
+		{"user/1005921515/state/com.google/starred", greaderStarred},
+		{"user/alice/label/News", "user/-/label/News"},
+		{"feed/https://example.com/feed", "feed/https://example.com/feed"},
+		{"user/-", "user/-"},
+	}
+	for _, tt := range tests {
+		if got := normalizeGReaderTag(tt.tag); got != tt.want {
+			t.Errorf("normalizeGReaderTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
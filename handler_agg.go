@@ -3,77 +3,308 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmartaudio/gator/internal/database"
-
-	"github.com/lib/pq"
 )
 
+// fetchTimeout keeps one slow server from holding a worker forever.
+const fetchTimeout = 30 * time.Second
+
 func handlerAgg(s *state, cmd command) error {
 	if len(cmd.Args) < 1 {
-		return fmt.Errorf("Command: %s provide a duration: 1s, 1m, 1h", cmd.Name)
+		return fmt.Errorf("Command: %s <duration> [concurrency] [batch_size] provide a duration: 1s, 1m, 1h", cmd.Name)
 	}
 	timeBetweenReqs, err := time.ParseDuration(cmd.Args[0])
 	if err != nil {
 		return fmt.Errorf("invalid duration: %w", err)
 	}
 
-	log.Printf("Collecting feeds every %s...", timeBetweenReqs)
+	concurrency := 1
+	if len(cmd.Args) > 1 {
+		concurrency, err = strconv.Atoi(cmd.Args[1])
+		if err != nil || concurrency < 1 {
+			return fmt.Errorf("concurrency must be a positive integer")
+		}
+	}
+	batchSize := concurrency
+	if len(cmd.Args) > 2 {
+		batchSize, err = strconv.Atoi(cmd.Args[2])
+		if err != nil || batchSize < 1 {
+			return fmt.Errorf("batch size must be a positive integer")
+		}
+	}
 
+	minInterval, maxInterval, err := s.cfg.FetchIntervals()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Collecting %d feeds every %s with %d workers...", batchSize, timeBetweenReqs, concurrency)
+
+	agg := &aggregator{
+		s:           s,
+		hosts:       newHostLimiter(),
+		workerID:    aggWorkerID(),
+		concurrency: concurrency,
+		batchSize:   batchSize,
+		minInterval: minInterval,
+		maxInterval: maxInterval,
+	}
 	ticker := time.NewTicker(timeBetweenReqs)
 	for ; ; <-ticker.C {
-		scrapeFeeds(s)
+		if err := agg.scrapeFeeds(); err != nil {
+			log.Printf("error collecting feeds: %v", err)
+		}
 	}
 }
 
-func scrapeFeeds(s *state) error {
-	currentTime := time.Now()
-	nullTime := sql.NullTime{Time: currentTime, Valid: true}
+// claimLease is how long a claimed feed stays reserved for one aggregator.
+// A whole batch is claimed at once, but feeds can then queue behind others on
+// the same host for longer than that, so each lease is renewed just before
+// its fetch. It only has to cover one fetch and storing its posts.
+const claimLease = 5 * time.Minute
 
-	next, err := s.db.GetNextFeedToFetch(context.Background())
+// aggregator is one agg process. Several may share a database, each claiming
+// feeds under its own workerID.
+type aggregator struct {
+	s           *state
+	hosts       *hostLimiter
+	workerID    string
+	concurrency int
+	batchSize   int
+	minInterval time.Duration
+	maxInterval time.Duration
+}
+
+func aggWorkerID() string {
+	host, err := os.Hostname()
 	if err != nil {
-		return err
+		host = "unknown"
 	}
-	err = s.db.MarkFeedFetched(context.Background(),
-		database.MarkFeedFetchedParams{
-			UpdatedAt:     currentTime,
-			LastFetchedAt: nullTime,
-			ID:            next.ID,
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// scrapeFeeds claims the next batchSize feeds that are due and fetches them on
+// at most concurrency goroutines, one request per host at a time.
+func (a *aggregator) scrapeFeeds() error {
+	feeds, err := a.s.db.ClaimFeedsToFetch(context.Background(),
+		database.ClaimFeedsToFetchParams{
+			ClaimedBy:    sql.NullString{String: a.workerID, Valid: true},
+			ClaimedUntil: sql.NullTime{Time: time.Now().Add(claimLease), Valid: true},
+			Limit:        int32(a.batchSize),
 		},
 	)
-	feed, err := fetchFeed(context.Background(), next.Url)
 	if err != nil {
 		return err
 	}
+
+	sem := make(chan struct{}, a.concurrency)
+	var wg sync.WaitGroup
+	for _, feed := range feeds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			unlock := a.hosts.lock(feed.Url)
+			defer unlock()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			held, err := a.s.db.RenewFeedClaim(context.Background(),
+				database.RenewFeedClaimParams{
+					ClaimedUntil: sql.NullTime{Time: time.Now().Add(claimLease), Valid: true},
+					ID:           feed.ID,
+					ClaimedBy:    sql.NullString{String: a.workerID, Valid: true},
+				},
+			)
+			if err != nil {
+				log.Printf("error renewing the claim on %s: %v", feed.Name, err)
+				return
+			}
+			if held == 0 {
+				// Waited out the lease and another aggregator took the feed.
+				return
+			}
+
+			rss, fetchErr := a.scrapeFeed(feed)
+			if fetchErr != nil {
+				log.Printf("error fetching %s: %v", feed.Name, fetchErr)
+			}
+			if err := a.finishFeed(feed, rss, fetchErr); err != nil {
+				log.Printf("error updating %s: %v", feed.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// scrapeFeed fetches one claimed feed and stores its posts. The parsed feed
+// is nil when the server had nothing new.
+func (a *aggregator) scrapeFeed(next database.Feed) (*RSSFeed, error) {
+	s := a.s
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	if next.PublicOnly {
+		ctx = publicOnly(ctx)
+	}
+	feed, validators, err := fetchFeedConditional(ctx, next.Url, cacheValidators{
+		ETag:         next.Etag.String,
+		LastModified: next.LastModified.String,
+	})
+	if errors.Is(err, errNotModified) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	shared := sharedLinks(feed.Channel.Item)
 	for _, item := range feed.Channel.Item {
-		post, err := s.db.CreatePost(context.Background(),
-			database.CreatePostParams{
-				ID:          uuid.New(),
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-				Title:       sql.NullString{String: item.Title, Valid: true},
-				Url:         item.Link,
-				Description: sql.NullString{String: item.Description, Valid: true},
-				PublishedAt: sql.NullString{String: string(item.PubDate), Valid: true},
-				FeedID:      next.ID,
+		now := time.Now()
+		publishedAt, ok := parsePubDate(item.PubDate)
+		if !ok {
+			// Marked as a guess, which the posting stats leave out.
+			publishedAt = now
+		}
+		guid := sql.NullString{String: item.identity(shared), Valid: true}
+		err := s.db.AdoptPostGuid(context.Background(),
+			database.AdoptPostGuidParams{Guid: guid, FeedID: next.ID, Url: item.Link},
+		)
+		if err != nil {
+			return nil, err
+		}
+		post, err := s.db.UpsertPost(context.Background(),
+			database.UpsertPostParams{
+				ID:                 uuid.New(),
+				CreatedAt:          now,
+				UpdatedAt:          now,
+				Title:              sql.NullString{String: item.Title, Valid: true},
+				Url:                item.Link,
+				Description:        sql.NullString{String: item.Description, Valid: true},
+				PublishedAt:        publishedAt,
+				FeedID:             next.ID,
+				Guid:               guid,
+				PublishedAtGuessed: !ok,
+				PublishedAtRaw: sql.NullString{
+					String: item.PubDate,
+					Valid:  item.PubDate != "",
+				},
+				Author: sql.NullString{
+					String: item.Author,
+					Valid:  item.Author != "",
+				},
+				EnclosureUrl: sql.NullString{
+					String: item.Enclosure.URL,
+					Valid:  item.Enclosure.URL != "",
+				},
+				EnclosureType: sql.NullString{
+					String: item.Enclosure.Type,
+					Valid:  item.Enclosure.Type != "",
+				},
 			},
 		)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Already stored and unchanged, or pruned.
+			continue
+		}
 		if err != nil {
-			if err, ok := err.(*pq.Error); ok {
-				if err.Code == "23505" {
-					continue
-				}
-			} else {
-				log.Println(err)
+			log.Println(err)
+			return nil, err
+		}
+		if post.Revision > 0 {
+			fmt.Printf("Updated Post %s From %s\n", post.Title.String, next.Name)
+		} else {
+			fmt.Printf("New Post %s From %s\n", post.Title.String, next.Name)
+		}
+	}
+
+	// Only remember the validators once every post is stored, otherwise a
+	// 304 on the next run would hide the ones we failed to save.
+	err = s.db.UpdateFeedCacheHeaders(context.Background(),
+		database.UpdateFeedCacheHeadersParams{
+			Etag:         sql.NullString{String: validators.ETag, Valid: validators.ETag != ""},
+			LastModified: sql.NullString{String: validators.LastModified, Valid: validators.LastModified != ""},
+			ID:           next.ID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// finishFeed releases the claim on feed and records how the fetch went. A
+// healthy feed is scheduled from its posting rate and the publisher's hints,
+// kept from rss when the feed was parsed, a failing one is pushed back
+// exponentially.
+func (a *aggregator) finishFeed(feed database.Feed, rss *RSSFeed, fetchErr error) error {
+	now := time.Now()
+	held, err := a.s.db.MarkFeedFetched(context.Background(),
+		database.MarkFeedFetchedParams{
+			UpdatedAt:     now,
+			LastFetchedAt: sql.NullTime{Time: now, Valid: true},
+			ID:            feed.ID,
+			ClaimedBy:     sql.NullString{String: a.workerID, Valid: true},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if held == 0 {
+		// The lease ran out and another aggregator has the feed now, its
+		// claim and its schedule are not ours to touch.
+		log.Printf("lost the claim on %s before it was done", feed.Name)
+		return nil
+	}
+
+	if fetchErr == nil {
+		if rss != nil {
+			hints := scheduleHints(rss)
+			hints.ID = feed.ID
+			if err := a.s.db.UpdateFeedScheduleHints(context.Background(), hints); err != nil {
 				return err
 			}
+			feed.MinIntervalSeconds = hints.MinIntervalSeconds
+			feed.SkipHours = hints.SkipHours
+			feed.SkipDays = hints.SkipDays
 		}
-		fmt.Printf("New Post %s From %s\n", post.Title.String, next.Name)
+		stats, err := a.s.db.GetFeedPostingStats(context.Background(), feed.ID)
+		if err != nil {
+			return err
+		}
+		interval := fetchInterval(feed, stats, a.minInterval, a.maxInterval)
+		nextFetch := skipAhead(now.Add(interval), feed)
+		return a.s.db.RecordFeedSuccess(context.Background(),
+			database.RecordFeedSuccessParams{
+				NextFetchAt: sql.NullTime{Time: nextFetch, Valid: true},
+				ID:          feed.ID,
+			},
+		)
 	}
 
-	return nil
+	var statusCode sql.NullInt32
+	var statusErr *httpStatusError
+	if errors.As(fetchErr, &statusErr) {
+		statusCode = sql.NullInt32{Int32: int32(statusErr.StatusCode), Valid: true}
+	}
+	failures := feed.ConsecutiveFailures + 1
+	return a.s.db.RecordFeedFailure(context.Background(),
+		database.RecordFeedFailureParams{
+			LastError:      sql.NullString{String: fetchErr.Error(), Valid: true},
+			LastErrorAt:    sql.NullTime{Time: now, Valid: true},
+			LastStatusCode: statusCode,
+			NextFetchAt:    sql.NullTime{Time: now.Add(backoffDelay(failures)), Valid: true},
+			ID:             feed.ID,
+		},
+	)
 }
@@ -2,49 +2,236 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"flag"
 	"fmt"
+	"slices"
 	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmartaudio/gator/internal/database"
 	"github.com/microcosm-cc/bluemonday"
 )
 
 func handlerBrowse(s *state, cmd command, user database.User) error {
-	var limit int32
-	limit = 2
-	if len(cmd.Args) > 0 {
-		argInt, err := strconv.ParseInt(cmd.Args[0], 10, 32)
+	var opts timelineOptions
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.BoolVar(&opts.All, "all", false, "include posts you have already read")
+	fs.StringVar(&opts.Before, "before", "", "only posts older than this post id")
+	fs.StringVar(&opts.After, "after", "", "only posts newer than this post id")
+	fs.IntVar(&opts.Page, "page", 1, "page of results, counted from the newest post or the cursor")
+	fs.Var((*stringList)(&opts.Feeds), "feed", "only posts from this feed name or url, may be repeated")
+	fs.StringVar(&opts.Since, "since", "", "only posts published after this age (30d, 12h) or date")
+	fs.StringVar(&opts.Until, "until", "", "only posts published before this age or date")
+	fs.BoolVar(&opts.Unread, "unread", false, "only unread posts, the default unless --all or --starred")
+	fs.BoolVar(&opts.Starred, "starred", false, "only starred posts")
+	fs.StringVar(&opts.Sort, "sort", "published", "order by published, fetched or title")
+	limitFlag := fs.Int("limit", 0, "number of posts to show")
+	fs.StringVar(&opts.Search, "search", "", "only posts matching this saved search")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+
+	opts.Limit = 10
+	if len(args) > 0 {
+		argInt, err := strconv.ParseInt(args[0], 10, 32)
 		if err != nil {
-			fmt.Println("Provide limit as an integer")
+			return fmt.Errorf("Provide limit as an integer")
 		}
-		limit = int32(argInt)
+		opts.Limit = int(argInt)
+	}
+	if *limitFlag != 0 {
+		opts.Limit = *limitFlag
+	}
+
+	params, err := timelineParams(s, user, opts)
+	if err != nil {
+		return err
 	}
-	feeds, err := s.db.GetFeeds(context.Background(), user.ID)
+
+	posts, err := s.db.GetTimelineForUser(context.Background(), params)
 	if err != nil {
 		return err
 	}
-	for _, feed := range feeds {
-		posts, err := s.db.GetPostForUser(context.Background(),
-			database.GetPostForUserParams{
-				FeedID: feed.ID,
-				Limit:  limit,
-			},
+	if params.Ascending {
+		slices.Reverse(posts)
+	}
+
+	if len(posts) < 1 {
+		fmt.Println("Nothing to read")
+		return nil
+	}
+	for _, post := range posts {
+		fmt.Printf(" * Feed:         %v\n", post.FeedName)
+		printPost(post.Post)
+	}
+	if len(posts) == opts.Limit && opts.Sort == "published" {
+		fmt.Printf("Older posts: %s --before %s\n", cmd.Name, posts[len(posts)-1].Post.ID)
+	}
+
+	return nil
+}
+
+// timelineOptions are the browse filters, shared by the CLI flags and the
+// query parameters of the API.
+type timelineOptions struct {
+	All     bool
+	Unread  bool
+	Starred bool
+	Feeds   []string
+	Since   string
+	Until   string
+	Search  string
+	Sort    string
+	Before  string
+	After   string
+	Limit   int
+	Page    int
+}
+
+// timelineParams checks opts and resolves the feeds, saved search and cursor
+// posts they name into the query's parameters.
+func timelineParams(s *state, user database.User, opts timelineOptions) (database.GetTimelineForUserParams, error) {
+	var params database.GetTimelineForUserParams
+	if opts.Sort == "" {
+		opts.Sort = "published"
+	}
+	if opts.Limit < 1 {
+		return params, fmt.Errorf("limit must be a positive integer")
+	}
+	if opts.Page < 1 {
+		return params, fmt.Errorf("page starts at 1")
+	}
+	if opts.Before != "" && opts.After != "" {
+		return params, fmt.Errorf("use only one of before and after")
+	}
+	if !slices.Contains([]string{"published", "fetched", "title"}, opts.Sort) {
+		return params, fmt.Errorf("sort must be published, fetched or title")
+	}
+	if opts.Sort != "published" && (opts.Before != "" || opts.After != "") {
+		return params, fmt.Errorf("before and after only work when sorting by published")
+	}
+
+	// Starred posts stay interesting after they are read, so starred
+	// shows both unless unread asks otherwise.
+	unreadOnly := opts.Unread || (!opts.All && !opts.Starred)
+
+	params = database.GetTimelineForUserParams{
+		UserID:      user.ID,
+		UnreadOnly:  unreadOnly,
+		StarredOnly: opts.Starred,
+		FeedIds:     []uuid.UUID{},
+		SortBy:      opts.Sort,
+		Limit:       int32(opts.Limit),
+		Offset:      int32((opts.Page - 1) * opts.Limit),
+	}
+	for _, nameOrURL := range opts.Feeds {
+		feed, err := s.db.GetFeedByNameOrUrl(context.Background(), nameOrURL)
+		if err != nil {
+			return params, fmt.Errorf("couldn't find feed %s: %w", nameOrURL, err)
+		}
+		params.FeedIds = append(params.FeedIds, feed.ID)
+	}
+	if opts.Search != "" {
+		if len(opts.Feeds) > 0 {
+			return params, fmt.Errorf("a saved search already picks its feeds, drop feed")
+		}
+		saved, err := s.db.GetSavedSearchByName(context.Background(),
+			database.GetSavedSearchByNameParams{UserID: user.ID, Name: opts.Search},
 		)
 		if err != nil {
-			return err
+			return params, fmt.Errorf("couldn't find saved search %s: %w", opts.Search, err)
+		}
+		applySavedSearch(&params, saved)
+	}
+	if opts.Since != "" {
+		t, err := parseSince(opts.Since)
+		if err != nil {
+			return params, err
 		}
-		for _, post := range posts {
-			printPost(post)
+		params.Since = sql.NullTime{Time: t, Valid: true}
+	}
+	if opts.Until != "" {
+		t, err := parseSince(opts.Until)
+		if err != nil {
+			return params, err
 		}
+		params.Until = sql.NullTime{Time: t, Valid: true}
+	}
+	if opts.Before != "" {
+		cursor, err := browseCursor(s, opts.Before)
+		if err != nil {
+			return params, err
+		}
+		params.BeforePublishedAt = sql.NullTime{Time: cursor.PublishedAt, Valid: true}
+		params.BeforeID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+	if opts.After != "" {
+		cursor, err := browseCursor(s, opts.After)
+		if err != nil {
+			return params, err
+		}
+		params.AfterPublishedAt = sql.NullTime{Time: cursor.PublishedAt, Valid: true}
+		params.AfterID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+		// Walk forward from the cursor, then flip back to newest first.
+		params.Ascending = true
+	}
+	return params, nil
+}
+
+// applySavedSearch narrows params to the query, feeds and window of saved.
+func applySavedSearch(params *database.GetTimelineForUserParams, saved database.SavedSearch) {
+	params.Query = sql.NullString{String: saved.TsQuery, Valid: true}
+	params.FeedIds = saved.FeedIds
+	params.Since = saved.SinceAt
+	if saved.SinceAgeSeconds.Valid {
+		age := time.Duration(saved.SinceAgeSeconds.Int64) * time.Second
+		params.Since = sql.NullTime{Time: time.Now().Add(-age), Valid: true}
 	}
+	params.Until = saved.UntilAt
+}
 
-	return nil
+// parseSince turns an age such as 30d or 12h into the time that long ago, or
+// reads value as a date. Bare dates are taken in the local zone.
+func parseSince(value string) (time.Time, error) {
+	if age, err := parseAge(value); err == nil {
+		return time.Now().Add(-age), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, ok := parsePubDate(value); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid age or date: %s", value)
+}
+
+// browseCursor looks up the post a --before or --after flag points at.
+func browseCursor(s *state, postID string) (database.Post, error) {
+	id, err := uuid.Parse(postID)
+	if err != nil {
+		return database.Post{}, fmt.Errorf("invalid post id: %w", err)
+	}
+	post, err := s.db.GetPostByID(context.Background(), id)
+	if err != nil {
+		return database.Post{}, fmt.Errorf("couldn't find post %s: %w", postID, err)
+	}
+	return post, nil
 }
 
 func printPost(post database.Post) {
 	p := bluemonday.StrictPolicy()
+	fmt.Printf(" * ID:           %v\n", post.ID)
 	fmt.Printf(" * Title:        %v\n", p.Sanitize(post.Title.String))
-	fmt.Printf(" * Published at: %v\n", p.Sanitize(post.PublishedAt.String))
+	fmt.Printf(" * Published at: %v\n", post.PublishedAt.Local().Format(time.RFC1123))
 	fmt.Printf(" * URL:          %v\n", p.Sanitize(post.Url))
 	fmt.Printf(" * Description:  %v\n", p.Sanitize(post.Description.String))
+	if post.Author.Valid {
+		fmt.Printf(" * Author:       %v\n", p.Sanitize(post.Author.String))
+	}
+	if post.EnclosureUrl.Valid {
+		fmt.Printf(" * Attachment:   %v\n", p.Sanitize(post.EnclosureUrl.String))
+	}
 }
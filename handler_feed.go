@@ -2,7 +2,11 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	neturl "net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,39 +14,142 @@ import (
 )
 
 func handlerAddFeed(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Run command %s with [name] <url>", cmd.Name)
+	}
+	var name, rawURL string
 	if len(cmd.Args) < 2 {
-		return fmt.Errorf("Run command %s witn <name> <url>", cmd.Name)
+		rawURL = cmd.Args[0]
+	} else {
+		name = cmd.Args[0]
+		rawURL = cmd.Args[1]
 	}
-	name := cmd.Args[0]
-	url := cmd.Args[1]
 
-	feed, err := s.db.AddFeed(context.Background(),
-		database.AddFeedParams{
-			ID:        uuid.New(),
-			CreatedAt: time.Now().UTC(),
-			UpdatedAt: time.Now().UTC(),
-			Name:      name,
-			Url:       url,
-			UserID:    user.ID,
-		},
-	)
+	feed, others, err := createFeed(context.Background(), s, user, name, rawURL, "")
 	if err != nil {
 		return err
 	}
-	_, err = s.db.CreateFeedFollow(context.Background(),
-		database.CreateFeedFollowParams{
+	printDiscovered(rawURL, feed.Url, others)
+	printfeed(feed)
+	return nil
+}
+
+var (
+	// errFeedExists is returned by createFeed for a url already stored.
+	errFeedExists = errors.New("feed already exists")
+	// errFeedNameTaken is returned by createFeed for a name already in use.
+	errFeedNameTaken = errors.New("feed name already taken")
+)
+
+// createFeed checks that rawURL, or a feed advertised by the page there, is a
+// feed gator can read, stores it and subscribes user. An empty name defaults
+// to the channel title, category is the follow's. Any other feeds the page
+// advertises are returned alongside.
+func createFeed(ctx context.Context, s *state, user database.User, name, rawURL, category string) (database.Feed, []string, error) {
+	found, err := resolveFeedURL(ctx, rawURL)
+	if err != nil {
+		return database.Feed{}, nil, fmt.Errorf("couldn't find a feed at %s: %w", rawURL, err)
+	}
+	url, others, rss := found.URL, found.Others, found.Feed
+
+	// Only feeds picked from a page's links haven't been downloaded yet.
+	if rss == nil {
+		ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		defer cancel()
+		rss, err = fetchFeed(ctx, url)
+		if err != nil {
+			return database.Feed{}, nil, fmt.Errorf("%s is not a feed gator can read: %w", url, err)
+		}
+	}
+
+	if name == "" {
+		name, err = defaultFeedName(s, rss, url)
+		if err != nil {
+			return database.Feed{}, nil, err
+		}
+	}
+
+	feed, err := addFeed(ctx, s, user, name, url, strings.TrimSpace(rss.Channel.Link), strings.TrimSpace(rss.Channel.Description))
+	if isUniqueViolation(err, "feeds_url_key") {
+		return database.Feed{}, nil, fmt.Errorf("%w: %s has already been added, use follow to subscribe to it", errFeedExists, url)
+	}
+	if isUniqueViolation(err, "feeds_name_key") {
+		return database.Feed{}, nil, fmt.Errorf("%w: a feed called %q already exists, pick another name", errFeedNameTaken, name)
+	}
+	if err != nil {
+		return database.Feed{}, nil, err
+	}
+	_, err = followFeed(s, user, feed.ID, category)
+	if err != nil {
+		return database.Feed{}, nil, err
+	}
+	return feed, others, nil
+}
+
+// printDiscovered tells the user which feed discovery picked for rawURL and
+// lists the others the page advertises.
+func printDiscovered(rawURL, feedURL string, others []string) {
+	if feedURL != rawURL {
+		fmt.Printf("Found feed %s\n", feedURL)
+	}
+	if len(others) > 0 {
+		fmt.Println("The page also advertises:")
+		for _, feed := range others {
+			fmt.Printf(" * %s\n", feed)
+		}
+	}
+}
+
+// defaultFeedName names a feed after its channel title, or its host when it
+// has none, made unique against the existing feeds.
+func defaultFeedName(s *state, rss *RSSFeed, feedURL string) (string, error) {
+	name := strings.TrimSpace(rss.Channel.Title)
+	if name == "" {
+		if u, err := neturl.Parse(feedURL); err == nil && u.Host != "" {
+			name = u.Host
+		} else {
+			name = feedURL
+		}
+	}
+	return uniqueFeedName(s, name)
+}
+
+// addFeed stores a new feed owned by user. link is the site's home page,
+// link and description may be empty. A feed added under a publicOnly ctx is
+// kept to public addresses for good.
+func addFeed(ctx context.Context, s *state, user database.User, name, url, link, description string) (database.Feed, error) {
+	return s.db.AddFeed(ctx,
+		database.AddFeedParams{
 			ID:        uuid.New(),
 			CreatedAt: time.Now().UTC(),
 			UpdatedAt: time.Now().UTC(),
+			Name:      name,
+			Url:       url,
 			UserID:    user.ID,
-			FeedID:    feed.ID,
+			Link:      sql.NullString{String: link, Valid: link != ""},
+			Description: sql.NullString{
+				String: description,
+				Valid:  description != "",
+			},
+			PublicOnly: isPublicOnly(ctx),
 		},
 	)
-	if err != nil {
-		return err
+}
+
+// uniqueFeedName returns name, or name with the first free " (n)" suffix if
+// another feed already uses it.
+func uniqueFeedName(s *state, name string) (string, error) {
+	candidate := name
+	for n := 2; ; n++ {
+		exists, err := s.db.FeedNameExists(context.Background(), candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (%d)", name, n)
 	}
-	printfeed(feed)
-	return nil
 }
 
 func handlerShowFeeds(s *state, cmd command) error {
@@ -69,11 +176,46 @@ func handlerShowFeeds(s *state, cmd command) error {
 	return nil
 }
 
+func handlerFeedErrors(s *state, cmd command) error {
+	feeds, err := s.db.GetFeedErrors(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(feeds) < 1 {
+		fmt.Println("No feeds are failing")
+		return nil
+	}
+	for _, feed := range feeds {
+		printFeedError(feed)
+	}
+	return nil
+}
+
 func printfeed(feed database.Feed) {
 	fmt.Printf(" * ID:        %v\n", feed.ID)
 	fmt.Printf(" * Name:      %v\n", feed.Name)
 	fmt.Printf(" * CreatedAt: %v\n", feed.CreatedAt)
 	fmt.Printf(" * UpdatedAt: %v\n", feed.UpdatedAt)
 	fmt.Printf(" * URL: %v\n", feed.Url)
+	if feed.Link.Valid {
+		fmt.Printf(" * Link: %v\n", feed.Link.String)
+	}
+	if feed.Description.Valid {
+		fmt.Printf(" * Description: %v\n", feed.Description.String)
+	}
 	fmt.Printf(" * UserID: %v\n", feed.UserID)
 }
+
+func printFeedError(feed database.Feed) {
+	fmt.Printf(" * Name:       %v\n", feed.Name)
+	fmt.Printf(" * URL:        %v\n", feed.Url)
+	fmt.Printf(" * Failures:   %v\n", feed.ConsecutiveFailures)
+	if feed.LastStatusCode.Valid {
+		fmt.Printf(" * HTTP:       %v\n", feed.LastStatusCode.Int32)
+	}
+	fmt.Printf(" * Error:      %v\n", feed.LastError.String)
+	fmt.Printf(" * Failed at:  %v\n", feed.LastErrorAt.Time.Local().Format(time.RFC1123))
+	if feed.NextFetchAt.Valid {
+		fmt.Printf(" * Next retry: %v\n", feed.NextFetchAt.Time.Local().Format(time.RFC1123))
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,26 +16,52 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 		return fmt.Errorf("Command: %s <URL>", cmd.Name)
 	}
 
-	url := cmd.Args[0]
+	feed, others, err := findFeedByURL(context.Background(), s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	printDiscovered(cmd.Args[0], feed.Url, others)
 
-	feed, err := s.db.GetFeedsByUrl(context.Background(), url)
+	follow, err := followFeed(s, user, feed.ID, "")
 	if err != nil {
 		return err
 	}
 
-	follow, err := s.db.CreateFeedFollow(context.Background(),
+	fmt.Printf("%s is following %s\n", follow.UserName, follow.FeedName)
+
+	return nil
+}
+
+// findFeedByURL looks a feed up by its url, or by the feed the page at url
+// links to, in which case the other feeds the page advertises are returned
+// too.
+func findFeedByURL(ctx context.Context, s *state, url string) (database.GetFeedsByUrlRow, []string, error) {
+	feed, err := s.db.GetFeedsByUrl(ctx, url)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return feed, nil, err
+	}
+	// Maybe it's the site rather than the feed itself.
+	found, err := resolveFeedURL(ctx, url)
+	if err != nil {
+		return database.GetFeedsByUrlRow{}, nil, fmt.Errorf("no feed with url %s: %w", url, err)
+	}
+	feed, err = s.db.GetFeedsByUrl(ctx, found.URL)
+	return feed, found.Others, err
+}
+
+// followFeed subscribes user to a feed, filed under category when it is not
+// empty.
+func followFeed(s *state, user database.User, feedID uuid.UUID, category string) (database.CreateFeedFollowRow, error) {
+	return s.db.CreateFeedFollow(context.Background(),
 		database.CreateFeedFollowParams{
 			ID:        uuid.New(),
 			CreatedAt: time.Now().UTC(),
 			UpdatedAt: time.Now().UTC(),
 			UserID:    user.ID,
-			FeedID:    feed.ID,
+			FeedID:    feedID,
+			Category:  sql.NullString{String: category, Valid: category != ""},
 		},
 	)
-
-	fmt.Printf("%s is following %s\n", follow.UserName, follow.FeedName)
-
-	return nil
 }
 
 func handlerListFollows(s *state, cmd command, user database.User) error {
@@ -48,7 +76,18 @@ func handlerListFollows(s *state, cmd command, user database.User) error {
 	} else {
 		fmt.Printf("%s is following:\n", user.Name)
 		for _, follow := range follows {
-			fmt.Println(follow.FeedName)
+			fmt.Printf("%s (%d unread)\n", follow.FeedName, follow.UnreadCount)
+		}
+	}
+
+	searches, err := s.db.GetSavedSearchesForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	if len(searches) > 0 {
+		fmt.Println("Saved searches:")
+		for _, search := range searches {
+			fmt.Printf("%s: %s (%d unread)\n", search.Name, search.Query, search.UnreadCount)
 		}
 	}
 
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+func handlerImportOPML(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <file>", cmd.Name)
+	}
+
+	data, err := os.ReadFile(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("couldn't read OPML file: %w", err)
+	}
+
+	var doc OPML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("couldn't parse OPML file: %w", err)
+	}
+
+	var added, followed, skipped, failed int
+	for _, sub := range doc.subscriptions() {
+		result, err := importSubscription(s, user, sub)
+		if err != nil {
+			failed++
+			fmt.Printf(" ! %s (%s): %v\n", sub.Title, sub.XMLURL, err)
+			continue
+		}
+		switch result {
+		case importAdded:
+			added++
+			fmt.Printf(" + %s\n", sub.Title)
+		case importFollowed:
+			followed++
+			fmt.Printf(" * %s\n", sub.Title)
+		case importSkipped:
+			skipped++
+		}
+	}
+
+	fmt.Printf("Imported %s: %d added, %d followed, %d skipped, %d failed\n",
+		cmd.Args[0], added, followed, skipped, failed)
+	return nil
+}
+
+func handlerExportOPML(s *state, cmd command, user database.User) error {
+	follows, err := s.db.GetFeedFollowsForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	doc := buildOPML(fmt.Sprintf("%s's gator subscriptions", user.Name), follows, time.Now())
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+
+	if len(cmd.Args) < 1 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(cmd.Args[0], data, 0o644); err != nil {
+		return fmt.Errorf("couldn't write OPML file: %w", err)
+	}
+	fmt.Printf("Exported %d feeds to %s\n", len(follows), cmd.Args[0])
+	return nil
+}
+
+type importResult int
+
+const (
+	importAdded importResult = iota
+	importFollowed
+	importSkipped
+)
+
+// importSubscription follows sub, creating the feed first when nobody has
+// added it yet, checked and named after its OPML title like addfeed would.
+// Feeds the user already follows are skipped.
+func importSubscription(s *state, user database.User, sub opmlSubscription) (importResult, error) {
+	existing, err := s.db.GetFeedsByUrl(context.Background(), sub.XMLURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		name := ""
+		if sub.Title != "" {
+			name, err = uniqueFeedName(s, sub.Title)
+			if err != nil {
+				return 0, err
+			}
+		}
+		_, _, err = createFeed(context.Background(), s, user, name, sub.XMLURL, sub.Category)
+		if err != nil {
+			return 0, err
+		}
+		return importAdded, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = followFeed(s, user, existing.ID, sub.Category)
+	if isUniqueViolation(err, "feed_follows_user_id_feed_id_key") {
+		return importSkipped, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return importFollowed, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handlerPrune deletes posts published longer ago than the given age.
+// Starred posts are always kept, and agg won't store the pruned ones again.
+func handlerPrune(s *state, cmd command) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <age> provide an age: 30d, 720h", cmd.Name)
+	}
+	age, err := parseAge(cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	count, err := s.db.DeleteOldPosts(context.Background(), time.Now().Add(-age))
+	if err != nil {
+		return fmt.Errorf("couldn't prune posts: %w", err)
+	}
+
+	fmt.Printf("Deleted %d posts older than %s\n", count, cmd.Args[0])
+	return nil
+}
+
+// parseAge is time.ParseDuration plus a "d" suffix for days.
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age: %s", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age: %w", err)
+	}
+	return age, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+func handlerRead(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <post-id>", cmd.Name)
+	}
+	postID, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %w", err)
+	}
+
+	err = s.db.MarkPostRead(context.Background(),
+		database.MarkPostReadParams{
+			UserID: user.ID,
+			PostID: postID,
+			ReadAt: time.Now(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't mark post as read: %w", err)
+	}
+
+	fmt.Printf("Marked %s as read\n", postID)
+	return nil
+}
+
+func handlerUnread(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <post-id>", cmd.Name)
+	}
+	postID, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %w", err)
+	}
+
+	err = s.db.MarkPostUnread(context.Background(),
+		database.MarkPostUnreadParams{
+			UserID: user.ID,
+			PostID: postID,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't mark post as unread: %w", err)
+	}
+
+	fmt.Printf("Marked %s as unread\n", postID)
+	return nil
+}
+
+func handlerMarkAllRead(s *state, cmd command, user database.User) error {
+	var feedID uuid.NullUUID
+	target := "every feed you follow"
+	if len(cmd.Args) > 0 {
+		feed, err := s.db.GetFeedByNameOrUrl(context.Background(), cmd.Args[0])
+		if err != nil {
+			return fmt.Errorf("couldn't find feed %s: %w", cmd.Args[0], err)
+		}
+		feedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+		target = feed.Name
+	}
+
+	count, err := s.db.MarkAllPostsRead(context.Background(),
+		database.MarkAllPostsReadParams{
+			ReadAt: time.Now(),
+			UserID: user.ID,
+			FeedID: feedID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Marked %d posts in %s as read\n", count, target)
+	return nil
+}
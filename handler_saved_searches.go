@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+func handlerSaveSearch(s *state, cmd command, user database.User) error {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	var feeds stringList
+	fs.Var(&feeds, "feed", "only search this feed name or url, may be repeated")
+	since := fs.String("since", "", "only posts from the last age (30d, 12h) or since a date")
+	until := fs.String("until", "", "only posts published before this date")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("Command: %s <name> <query> [--feed <name|url>] [--since <age|date>] [--until <date>]", cmd.Name)
+	}
+
+	name := args[0]
+	query := joinSearchArgs(args[1:])
+	tsQuery, err := toTSQuery(query)
+	if err != nil {
+		return err
+	}
+
+	params := database.SaveSearchParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		Name:      name,
+		Query:     query,
+		TsQuery:   tsQuery,
+		FeedIds:   []uuid.UUID{},
+	}
+	for _, nameOrURL := range feeds {
+		feed, err := s.db.GetFeedByNameOrUrl(context.Background(), nameOrURL)
+		if err != nil {
+			return fmt.Errorf("couldn't find feed %s: %w", nameOrURL, err)
+		}
+		params.FeedIds = append(params.FeedIds, feed.ID)
+	}
+	// An age keeps the window rolling, a date pins its start.
+	if *since != "" {
+		if age, err := parseAge(*since); err == nil {
+			params.SinceAgeSeconds = sql.NullInt64{Int64: int64(age / time.Second), Valid: true}
+		} else {
+			t, err := parseSince(*since)
+			if err != nil {
+				return err
+			}
+			params.SinceAt = sql.NullTime{Time: t, Valid: true}
+		}
+	}
+	if *until != "" {
+		t, err := parseSince(*until)
+		if err != nil {
+			return err
+		}
+		params.UntilAt = sql.NullTime{Time: t, Valid: true}
+	}
+
+	saved, err := s.db.SaveSearch(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("couldn't save search: %w", err)
+	}
+
+	fmt.Printf("Saved search %s for %s, browse it with: browse --search %s\n", saved.Name, saved.Query, saved.Name)
+	return nil
+}
+
+func handlerUnsaveSearch(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <name>", cmd.Name)
+	}
+
+	deleted, err := s.db.DeleteSavedSearch(context.Background(),
+		database.DeleteSavedSearchParams{UserID: user.ID, Name: cmd.Args[0]},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't delete saved search: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("no saved search named %s", cmd.Args[0])
+	}
+
+	fmt.Printf("Deleted saved search %s\n", cmd.Args[0])
+	return nil
+}
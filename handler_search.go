@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jmartaudio/gator/internal/database"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+func handlerSearch(s *state, cmd command, user database.User) error {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	limit := fs.Int("limit", 10, "number of results to show")
+	args, err := parseFlags(fs, cmd.Args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("Command: %s <query> [--limit n]", cmd.Name)
+	}
+	if *limit < 1 {
+		return fmt.Errorf("--limit must be a positive integer")
+	}
+
+	query, err := toTSQuery(joinSearchArgs(args))
+	if err != nil {
+		return err
+	}
+
+	results, err := s.db.SearchPostsForUser(context.Background(),
+		database.SearchPostsForUserParams{
+			Query:  query,
+			UserID: user.ID,
+			Limit:  int32(*limit),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't search posts: %w", err)
+	}
+
+	if len(results) < 1 {
+		fmt.Println("No posts found")
+		return nil
+	}
+	p := bluemonday.StrictPolicy()
+	for _, result := range results {
+		fmt.Printf(" * Feed:         %v\n", result.FeedName)
+		fmt.Printf(" * ID:           %v\n", result.Post.ID)
+		fmt.Printf(" * Title:        %v\n", highlight(p.Sanitize(result.TitleHeadline)))
+		fmt.Printf(" * Published at: %v\n", result.Post.PublishedAt.Local().Format(time.RFC1123))
+		fmt.Printf(" * URL:          %v\n", p.Sanitize(result.Post.Url))
+		fmt.Printf(" * Match:        %v\n", highlight(p.Sanitize(result.DescriptionHeadline)))
+		fmt.Printf(" * Rank:         %.3f\n", result.Rank)
+	}
+
+	return nil
+}
+
+// highlight swaps the match markers ts_headline puts around search hits for
+// terminal colours, or drops them when NO_COLOR is set.
+func highlight(text string) string {
+	start, stop := "\033[1;33m", "\033[0m"
+	if os.Getenv("NO_COLOR") != "" {
+		start, stop = "", ""
+	}
+	return strings.NewReplacer("\x01", start, "\x02", stop).Replace(text)
+}
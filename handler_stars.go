@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+func handlerStar(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <post-id>", cmd.Name)
+	}
+	postID, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %w", err)
+	}
+
+	err = s.db.StarPost(context.Background(),
+		database.StarPostParams{
+			UserID:    user.ID,
+			PostID:    postID,
+			StarredAt: time.Now(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't star post: %w", err)
+	}
+
+	fmt.Printf("Starred %s\n", postID)
+	return nil
+}
+
+func handlerUnstar(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <post-id>", cmd.Name)
+	}
+	postID, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid post id: %w", err)
+	}
+
+	err = s.db.UnstarPost(context.Background(),
+		database.UnstarPostParams{
+			UserID: user.ID,
+			PostID: postID,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't unstar post: %w", err)
+	}
+
+	fmt.Printf("Unstarred %s\n", postID)
+	return nil
+}
+
+func handlerStarred(s *state, cmd command, user database.User) error {
+	var limit int32
+	limit = 20
+	if len(cmd.Args) > 0 {
+		argInt, err := strconv.ParseInt(cmd.Args[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("Provide limit as an integer")
+		}
+		limit = int32(argInt)
+	}
+
+	posts, err := s.db.GetStarredPostsForUser(context.Background(),
+		database.GetStarredPostsForUserParams{
+			UserID: user.ID,
+			Limit:  limit,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if len(posts) < 1 {
+		fmt.Println("You have not starred anything")
+		return nil
+	}
+	for _, post := range posts {
+		printPost(post)
+	}
+	return nil
+}
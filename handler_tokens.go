@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+func handlerCreateToken(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <name>", cmd.Name)
+	}
+	name := cmd.Args[0]
+
+	token := newAPIToken()
+	_, err := s.db.CreateApiToken(context.Background(),
+		database.CreateApiTokenParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			Name:      name,
+			TokenHash: hashAPIToken(token),
+		},
+	)
+	if isUniqueViolation(err, "api_tokens_user_id_name_key") {
+		return fmt.Errorf("you already have a token called %q, revoke it first", name)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't create token: %w", err)
+	}
+
+	fmt.Printf("Token %s for %s, it won't be shown again:\n%s\n", name, user.Name, token)
+	return nil
+}
+
+func handlerListTokens(s *state, cmd command, user database.User) error {
+	tokens, err := s.db.GetApiTokensForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) < 1 {
+		fmt.Println("You have no API tokens")
+		return nil
+	}
+	for _, token := range tokens {
+		lastUsed := "never"
+		if token.LastUsedAt.Valid {
+			lastUsed = token.LastUsedAt.Time.Local().Format(time.RFC1123)
+		}
+		fmt.Printf("* %s (created %s, last used %s)\n", token.Name, token.CreatedAt.Local().Format(time.RFC1123), lastUsed)
+	}
+	return nil
+}
+
+func handlerRevokeToken(s *state, cmd command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return fmt.Errorf("Command: %s <name>", cmd.Name)
+	}
+
+	deleted, err := s.db.DeleteApiToken(context.Background(),
+		database.DeleteApiTokenParams{UserID: user.ID, Name: cmd.Args[0]},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't revoke token: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("no token named %s", cmd.Args[0])
+	}
+
+	fmt.Printf("Revoked token %s\n", cmd.Args[0])
+	return nil
+}
+
+func newAPIToken() string {
+	return "gator_" + rand.Text()
+}
+
+// hashAPIToken is what a token is stored and looked up by. Tokens are long
+// and random, so a plain SHA-256 is enough.
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
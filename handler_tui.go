@@ -0,0 +1,657 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+	"github.com/lib/pq"
+)
+
+// The panes of the tui, left to right.
+const (
+	tuiFeeds = iota
+	tuiPosts
+	tuiReader
+)
+
+const (
+	tuiPageSize = 100
+	// tuiSettle is how long to wait after a new post for the rest of agg's
+	// batch before refreshing, so a busy feed doesn't redraw once per post.
+	tuiSettle = time.Second
+	tuiHelp   = "j/k move  h/l pane  enter open  n/p next/prev  m read  s star  A all read  a show read  r refresh  q quit"
+)
+
+// tuiSource is a line in the feeds pane and the posts it lists.
+type tuiSource struct {
+	key    string
+	label  string
+	unread int64
+	feedID uuid.NullUUID
+	opts   timelineOptions
+}
+
+type tuiLine struct {
+	text  string
+	style string
+}
+
+type tuiModel struct {
+	s       *state
+	user    database.User
+	sources []tuiSource
+	source  int
+	posts   []database.GetTimelineForUserRow
+	post    int
+	more    bool
+	showAll bool
+	focus   int
+	scroll  int
+	status  string
+	width   int
+	height  int
+
+	textID uuid.UUID
+	text   string
+}
+
+func handlerTUI(s *state, cmd command, user database.User) error {
+	t := &tuiModel{s: s, user: user, focus: tuiPosts}
+	if err := t.loadSources(); err != nil {
+		return err
+	}
+	if err := t.loadPosts(); err != nil {
+		return err
+	}
+
+	// agg's inserts fire a notification on gator_new_posts, see migration 023.
+	listener := pq.NewListener(s.cfg.DBURL, 10*time.Second, time.Minute, nil)
+	defer listener.Close()
+	if err := listener.Listen("gator_new_posts"); err != nil {
+		t.status = fmt.Sprintf("Not watching for new posts: %v", err)
+	}
+
+	tty, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer tty.close()
+
+	keys := make(chan string)
+	go tty.readKeys(keys)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(stop)
+	// Polling the size works everywhere, SIGWINCH only on unix.
+	resize := time.NewTicker(250 * time.Millisecond)
+	defer resize.Stop()
+
+	var settle <-chan time.Time
+	t.width, t.height = tty.size()
+	for {
+		t.draw(tty.out)
+		select {
+		case key, ok := <-keys:
+			if !ok || !t.handleKey(key) {
+				return nil
+			}
+		case <-resize.C:
+			width, height := tty.size()
+			if width == t.width && height == t.height {
+				continue
+			}
+			t.width, t.height = width, height
+			tty.out.WriteString("\x1b[2J")
+		case <-listener.Notify:
+			// A nil notification means the connection was re-established
+			// and some may have been missed, which a refresh also covers.
+			if settle == nil {
+				settle = time.After(tuiSettle)
+			}
+		case <-settle:
+			settle = nil
+			t.refreshNew()
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// handleKey acts on a key press and reports whether to keep running.
+func (t *tuiModel) handleKey(key string) bool {
+	t.status = ""
+	page := max(1, t.height-2)
+	switch key {
+	case "q", "ctrl-c":
+		return false
+	case "tab":
+		t.focus = (t.focus + 1) % 3
+	case "h", "left", "esc", "backspace":
+		if t.focus > tuiFeeds {
+			t.focus--
+		}
+	case "l", "right", "enter":
+		switch t.focus {
+		case tuiFeeds:
+			t.focus = tuiPosts
+		case tuiPosts:
+			t.open()
+		}
+	case "j", "down":
+		t.move(1)
+	case "k", "up":
+		t.move(-1)
+	case "ctrl-d":
+		t.move(page / 2)
+	case "ctrl-u":
+		t.move(-page / 2)
+	case "pgdown", " ", "ctrl-f":
+		t.move(page)
+	case "pgup", "b", "ctrl-b":
+		t.move(-page)
+	case "g", "home":
+		t.move(-1 << 30)
+	case "G", "end":
+		t.move(1 << 30)
+	case "n":
+		if t.post+1 < len(t.posts) {
+			t.selectPost(t.post + 1)
+			t.open()
+		}
+	case "p":
+		if t.post > 0 {
+			t.selectPost(t.post - 1)
+			t.open()
+		}
+	case "m":
+		if post, ok := t.current(); ok {
+			t.setRead(!post.IsRead)
+		}
+	case "s":
+		t.toggleStar()
+	case "A":
+		t.markAllRead()
+	case "a":
+		t.showAll = !t.showAll
+		t.post, t.scroll = 0, 0
+		t.report(t.loadPosts())
+	case "r":
+		t.report(t.loadSources())
+		t.report(t.loadPosts())
+	case "?":
+		t.status = tuiHelp
+	}
+	return true
+}
+
+func (t *tuiModel) move(delta int) {
+	switch t.focus {
+	case tuiFeeds:
+		next := min(max(t.source+delta, 0), len(t.sources)-1)
+		if next != t.source {
+			t.source = next
+			t.post, t.scroll = 0, 0
+			t.report(t.loadPosts())
+		}
+	case tuiPosts:
+		t.selectPost(t.post + delta)
+	case tuiReader:
+		// draw keeps this from scrolling past the end.
+		t.scroll = max(t.scroll+delta, 0)
+	}
+}
+
+// selectPost moves to post i, fetching the next page when it is the last
+// one loaded.
+func (t *tuiModel) selectPost(i int) {
+	if len(t.posts) == 0 {
+		return
+	}
+	t.post = min(max(i, 0), len(t.posts)-1)
+	t.scroll = 0
+	if t.post == len(t.posts)-1 && t.more {
+		t.report(t.loadMore())
+	}
+}
+
+func (t *tuiModel) current() (database.GetTimelineForUserRow, bool) {
+	if t.post >= len(t.posts) {
+		return database.GetTimelineForUserRow{}, false
+	}
+	return t.posts[t.post], true
+}
+
+// open shows the selected post in the reader pane and marks it read.
+func (t *tuiModel) open() {
+	post, ok := t.current()
+	if !ok {
+		return
+	}
+	t.focus, t.scroll = tuiReader, 0
+	if !post.IsRead {
+		t.setRead(true)
+	}
+}
+
+func (t *tuiModel) setRead(read bool) {
+	post, ok := t.current()
+	if !ok {
+		return
+	}
+	var err error
+	if read {
+		err = t.s.db.MarkPostRead(context.Background(),
+			database.MarkPostReadParams{UserID: t.user.ID, PostID: post.Post.ID, ReadAt: time.Now()},
+		)
+	} else {
+		err = t.s.db.MarkPostUnread(context.Background(),
+			database.MarkPostUnreadParams{UserID: t.user.ID, PostID: post.Post.ID},
+		)
+	}
+	if err != nil {
+		t.report(err)
+		return
+	}
+	// The post stays listed until the next refresh, even in the unread view.
+	t.posts[t.post].IsRead = read
+	t.report(t.loadSources())
+}
+
+func (t *tuiModel) toggleStar() {
+	post, ok := t.current()
+	if !ok {
+		return
+	}
+	var err error
+	if post.IsStarred {
+		err = t.s.db.UnstarPost(context.Background(),
+			database.UnstarPostParams{UserID: t.user.ID, PostID: post.Post.ID},
+		)
+	} else {
+		err = t.s.db.StarPost(context.Background(),
+			database.StarPostParams{UserID: t.user.ID, PostID: post.Post.ID, StarredAt: time.Now()},
+		)
+	}
+	if err != nil {
+		t.report(err)
+		return
+	}
+	t.posts[t.post].IsStarred = !post.IsStarred
+}
+
+// markAllRead marks everything in the selected source as read: a whole feed,
+// every followed feed, or for starred and saved searches the posts listed.
+func (t *tuiModel) markAllRead() {
+	source := t.sources[t.source]
+	var count int64
+	var err error
+	if source.feedID.Valid || source.key == "unread" {
+		count, err = t.s.db.MarkAllPostsRead(context.Background(),
+			database.MarkAllPostsReadParams{
+				ReadAt: time.Now(),
+				UserID: t.user.ID,
+				FeedID: source.feedID,
+			},
+		)
+	} else {
+		for _, post := range t.posts {
+			if post.IsRead {
+				continue
+			}
+			err = t.s.db.MarkPostRead(context.Background(),
+				database.MarkPostReadParams{UserID: t.user.ID, PostID: post.Post.ID, ReadAt: time.Now()},
+			)
+			if err != nil {
+				break
+			}
+			count++
+		}
+	}
+	if err != nil {
+		t.report(err)
+		return
+	}
+	for i := range t.posts {
+		t.posts[i].IsRead = true
+	}
+	t.report(t.loadSources())
+	t.status = fmt.Sprintf("Marked %d posts in %s as read", count, source.label)
+}
+
+// report shows err in the status line rather than leaving the tui over it.
+func (t *tuiModel) report(err error) {
+	if err != nil {
+		t.status = "Error: " + err.Error()
+	}
+}
+
+// loadSources reloads the feeds pane and its unread counts, keeping the
+// selection where it was.
+func (t *tuiModel) loadSources() error {
+	follows, err := t.s.db.GetFeedFollowsForUser(context.Background(), t.user.ID)
+	if err != nil {
+		return err
+	}
+	searches, err := t.s.db.GetSavedSearchesForUser(context.Background(), t.user.ID)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, follow := range follows {
+		total += follow.UnreadCount
+	}
+	sources := []tuiSource{
+		{key: "unread", label: "Unread", unread: total},
+		{key: "starred", label: "Starred", opts: timelineOptions{Starred: true}},
+	}
+	for _, follow := range follows {
+		sources = append(sources, tuiSource{
+			key:    "feed:" + follow.FeedID.String(),
+			label:  cleanLine(follow.FeedName),
+			unread: follow.UnreadCount,
+			feedID: uuid.NullUUID{UUID: follow.FeedID, Valid: true},
+			opts:   timelineOptions{Feeds: []string{follow.FeedUrl}},
+		})
+	}
+	for _, search := range searches {
+		sources = append(sources, tuiSource{
+			key:    "search:" + search.Name,
+			label:  "/" + cleanLine(search.Name),
+			unread: search.UnreadCount,
+			opts:   timelineOptions{Search: search.Name},
+		})
+	}
+
+	selected := ""
+	if t.source < len(t.sources) {
+		selected = t.sources[t.source].key
+	}
+	t.sources, t.source = sources, 0
+	for i, source := range sources {
+		if source.key == selected {
+			t.source = i
+		}
+	}
+	return nil
+}
+
+// loadPosts reloads the posts pane from the selected source, staying on the
+// selected post if it is still listed.
+func (t *tuiModel) loadPosts() error {
+	posts, err := t.fetch(func(opts *timelineOptions) {})
+	if err != nil {
+		return err
+	}
+	var selected uuid.UUID
+	if post, ok := t.current(); ok {
+		selected = post.Post.ID
+	}
+	t.posts, t.more = posts, len(posts) == tuiPageSize
+	t.post = min(t.post, max(len(posts)-1, 0))
+	for i, post := range posts {
+		if post.Post.ID == selected {
+			t.post = i
+		}
+	}
+	return nil
+}
+
+func (t *tuiModel) loadMore() error {
+	last := t.posts[len(t.posts)-1].Post.ID.String()
+	posts, err := t.fetch(func(opts *timelineOptions) { opts.Before = last })
+	if err != nil {
+		return err
+	}
+	t.posts, t.more = append(t.posts, posts...), len(posts) == tuiPageSize
+	return nil
+}
+
+// refreshNew is the live refresh after agg stores posts: counts and the
+// posts pane are reloaded, as far down as the list had been paged, and the
+// post being read stays selected even if it no longer matches the source.
+// Posts are counted as new by when they were fetched, not published, since
+// a feed can deliver posts dated before the ones already listed.
+func (t *tuiModel) refreshNew() {
+	if err := t.loadSources(); err != nil {
+		t.report(err)
+		return
+	}
+
+	var newest time.Time
+	for _, post := range t.posts {
+		if post.Post.CreatedAt.After(newest) {
+			newest = post.Post.CreatedAt
+		}
+	}
+	current, selected := t.current()
+	limit := max(len(t.posts), tuiPageSize)
+	posts, err := t.fetch(func(opts *timelineOptions) { opts.Limit = limit })
+	if err != nil {
+		t.report(err)
+		return
+	}
+
+	fresh, found := 0, false
+	for i, post := range posts {
+		if post.Post.CreatedAt.After(newest) {
+			fresh++
+		}
+		if selected && post.Post.ID == current.Post.ID {
+			t.post, found = i, true
+		}
+	}
+	t.more = len(posts) == limit
+	switch {
+	case found:
+	case selected:
+		// Read elsewhere or unfollowed, keep it until the user moves on.
+		t.post = min(t.post, len(posts))
+		posts = slices.Insert(posts, t.post, current)
+	default:
+		t.post = 0
+	}
+	t.posts = posts
+	if fresh > 0 {
+		t.status = fmt.Sprintf("%d new posts", fresh)
+	}
+}
+
+func (t *tuiModel) fetch(cursor func(opts *timelineOptions)) ([]database.GetTimelineForUserRow, error) {
+	opts := t.sources[t.source].opts
+	opts.All = opts.All || t.showAll
+	opts.Limit, opts.Page = tuiPageSize, 1
+	cursor(&opts)
+
+	params, err := timelineParams(t.s, t.user, opts)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := t.s.db.GetTimelineForUser(context.Background(), params)
+	if err != nil {
+		return nil, err
+	}
+	if params.Ascending {
+		slices.Reverse(posts)
+	}
+	return posts, nil
+}
+
+func (t *tuiModel) draw(out *bufio.Writer) {
+	body := max(t.height-2, 0)
+	feedsWidth := min(max(t.width/5, 12), 28)
+	postsWidth := min(max((t.width-feedsWidth)*2/5, 20), 60)
+	readerWidth := t.width - feedsWidth - postsWidth - 2
+
+	sourceLabel := t.sources[t.source].label
+	if t.showAll {
+		sourceLabel += " (all)"
+	}
+	readerLabel := "Reader"
+	if post, ok := t.current(); ok {
+		readerLabel = cleanLine(post.FeedName)
+	}
+	header := t.header("Feeds", feedsWidth, tuiFeeds) + "\x1b[2m│\x1b[0m" +
+		t.header(sourceLabel, postsWidth, tuiPosts) + "\x1b[2m│\x1b[0m" +
+		t.header(readerLabel, readerWidth, tuiReader)
+
+	feeds := t.feedLines(feedsWidth, body)
+	posts := t.postLines(postsWidth, body)
+	reader := t.readerLines(readerWidth, body)
+
+	out.WriteString("\x1b[H" + header)
+	for row := 0; row < body; row++ {
+		fmt.Fprintf(out, "\x1b[%d;1H%s\x1b[2m│\x1b[0m%s\x1b[2m│\x1b[0m%s", row+2, feeds[row], posts[row], reader[row])
+	}
+	status := t.status
+	if status == "" {
+		status = "? for keys"
+	}
+	fmt.Fprintf(out, "\x1b[%d;1H\x1b[7m%s\x1b[0m", t.height, fit(" "+status, t.width))
+	out.Flush()
+}
+
+func (t *tuiModel) header(label string, width, pane int) string {
+	style := "\x1b[1m"
+	if t.focus == pane {
+		style = "\x1b[1;7m"
+	}
+	return style + fit(" "+label, width) + "\x1b[0m"
+}
+
+// window is the first of count lines to show in height rows so that
+// selected stays in view.
+func window(selected, count, height int) int {
+	return max(min(selected-height/2, count-height), 0)
+}
+
+func (t *tuiModel) feedLines(width, height int) []string {
+	lines := make([]string, height)
+	start := window(t.source, len(t.sources), height)
+	for row := range lines {
+		i := start + row
+		if i >= len(t.sources) {
+			lines[row] = fit("", width)
+			continue
+		}
+		source := t.sources[i]
+		count := ""
+		if source.unread > 0 {
+			count = strconv.FormatInt(source.unread, 10) + " "
+		}
+		line := fit(" "+source.label, width-len(count)) + count
+		if i == t.source {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		lines[row] = line
+	}
+	return lines
+}
+
+func (t *tuiModel) postLines(width, height int) []string {
+	lines := make([]string, height)
+	start := window(t.post, len(t.posts), height)
+	for row := range lines {
+		i := start + row
+		if i >= len(t.posts) {
+			lines[row] = fit("", width)
+			if i == 0 && row == 0 {
+				lines[row] = fit(" Nothing to read", width)
+			}
+			continue
+		}
+		post := t.posts[i]
+		read, star := " ", " "
+		if !post.IsRead {
+			read = "●"
+		}
+		if post.IsStarred {
+			star = "★"
+		}
+		// Four columns of marks, the title, then the date if there's room.
+		date := post.Post.PublishedAt.Local().Format(" Jan 02 ")
+		title := plainTitle(post.Post)
+		line := " " + read + star + " " + fit(title, width-4-len(date)) + date
+		if width < 30 {
+			line = fit(" "+read+star+" "+title, width)
+		}
+		style := ""
+		if !post.IsRead {
+			style += "\x1b[1m"
+		}
+		if i == t.post {
+			style += "\x1b[7m"
+		}
+		lines[row] = style + line + "\x1b[0m"
+	}
+	return lines
+}
+
+func (t *tuiModel) readerLines(width, height int) []string {
+	lines := make([]string, height)
+	post, ok := t.current()
+	var content []tuiLine
+	if ok {
+		content = t.postText(post, width-2)
+	}
+
+	t.scroll = min(t.scroll, max(len(content)-height, 0))
+	for row := range lines {
+		i := t.scroll + row
+		if i >= len(content) {
+			lines[row] = fit("", width)
+			continue
+		}
+		lines[row] = content[i].style + fit(" "+content[i].text, width) + "\x1b[0m"
+	}
+	return lines
+}
+
+func (t *tuiModel) postText(row database.GetTimelineForUserRow, width int) []tuiLine {
+	post := row.Post
+	if t.textID != post.ID {
+		t.textID, t.text = post.ID, htmlToText(post.Description.String)
+	}
+
+	var lines []tuiLine
+	add := func(text, style string) {
+		for _, line := range wrapText(text, width) {
+			lines = append(lines, tuiLine{text: line, style: style})
+		}
+	}
+	add(plainTitle(post), "\x1b[1m")
+	meta := []string{cleanLine(row.FeedName), post.PublishedAt.Local().Format("2 Jan 2006 15:04")}
+	if post.Author.Valid {
+		meta = append(meta, cleanLine(post.Author.String))
+	}
+	if row.IsStarred {
+		meta = append(meta, "★ starred")
+	}
+	add(strings.Join(meta, " · "), "\x1b[2m")
+	add(cleanLine(post.Url), "\x1b[2m")
+	add("", "")
+	add(t.text, "")
+	if post.EnclosureUrl.Valid {
+		add("", "")
+		add("Attachment: "+cleanLine(post.EnclosureUrl.String), "\x1b[2m")
+	}
+	return lines
+}
+
+func plainTitle(post database.Post) string {
+	title := strings.Join(strings.Fields(htmlToText(post.Title.String)), " ")
+	if title == "" {
+		return cleanLine(post.Url)
+	}
+	return title
+}
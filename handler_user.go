@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -74,6 +80,44 @@ func handlerGetUsers(s *state, cmd command) error {
 	return nil
 }
 
+// handlerPasswd sets the password sync clients log in with. It is read from
+// stdin so it stays out of the shell history.
+func handlerPasswd(s *state, cmd command, user database.User) error {
+	fmt.Fprintf(os.Stderr, "New password for %s: ", user.Name)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("couldn't read password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if len(password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = s.db.SetUserPassword(context.Background(),
+		database.SetUserPasswordParams{
+			UserID:          user.ID,
+			PasswordHash:    hash,
+			UpdatedAt:       time.Now().UTC(),
+			FeverApiKeyHash: sql.NullString{String: hashAPIToken(feverAPIKey(user.Name, password)), Valid: true},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't set password: %w", err)
+	}
+	// A new password signs every browser out, as it does sync clients.
+	err = s.db.DeleteWebSessionsForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't end web sessions: %w", err)
+	}
+
+	fmt.Printf("Password set for %s\n", user.Name)
+	return nil
+}
+
 func printUser(user database.User) {
 	fmt.Printf(" * ID:        %v\n", user.ID)
 	fmt.Printf(" * Name:      %v\n", user.Name)
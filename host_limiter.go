@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// hostLimiter hands out one lock per host so concurrent workers never have
+// more than one request in flight against the same site.
+type hostLimiter struct {
+	mu    sync.Mutex
+	hosts map[string]*sync.Mutex
+}
+
+func newHostLimiter() *hostLimiter {
+	return &hostLimiter{hosts: make(map[string]*sync.Mutex)}
+}
+
+// lock blocks until rawURL's host is free and returns the matching unlock.
+func (h *hostLimiter) lock(rawURL string) func() {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = strings.ToLower(u.Hostname())
+	}
+
+	h.mu.Lock()
+	m, ok := h.hosts[host]
+	if !ok {
+		m = &sync.Mutex{}
+		h.hosts[host] = m
+	}
+	h.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
@@ -2,15 +2,24 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const configFileName = ".gatorconfig.json"
 
+const (
+	defaultMinFetchInterval = 15 * time.Minute
+	defaultMaxFetchInterval = 24 * time.Hour
+)
+
 type Config struct {
-	DBURL           string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DBURL            string `json:"db_url"`
+	CurrentUserName  string `json:"current_user_name"`
+	MinFetchInterval string `json:"min_fetch_interval,omitempty"`
+	MaxFetchInterval string `json:"max_fetch_interval,omitempty"`
 }
 
 func (cfg *Config) SetUser(userName string) error {
@@ -18,6 +27,32 @@ func (cfg *Config) SetUser(userName string) error {
 	return write(*cfg)
 }
 
+// FetchIntervals returns the bounds for how often agg polls a single feed,
+// falling back to 15m and 24h when they are not set.
+func (cfg *Config) FetchIntervals() (time.Duration, time.Duration, error) {
+	minInterval := defaultMinFetchInterval
+	maxInterval := defaultMaxFetchInterval
+
+	var err error
+	if cfg.MinFetchInterval != "" {
+		minInterval, err = time.ParseDuration(cfg.MinFetchInterval)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid min_fetch_interval: %w", err)
+		}
+	}
+	if cfg.MaxFetchInterval != "" {
+		maxInterval, err = time.ParseDuration(cfg.MaxFetchInterval)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid max_fetch_interval: %w", err)
+		}
+	}
+	if minInterval > maxInterval {
+		return 0, 0, fmt.Errorf("min_fetch_interval %s is longer than max_fetch_interval %s", minInterval, maxInterval)
+	}
+
+	return minInterval, maxInterval, nil
+}
+
 func getConfigFilePath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -7,31 +7,39 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const addFeed = `-- name: AddFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, link, description, public_only)
 VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7,
+    $8,
+    $9
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_by, claimed_until, etag, last_modified, last_error, last_error_at, last_status_code, consecutive_failures, next_fetch_at, link, description, numeric_id, min_interval_seconds, skip_hours, skip_days, public_only
 `
 
 type AddFeedParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Url         string
+	UserID      uuid.UUID
+	Link        sql.NullString
+	Description sql.NullString
+	PublicOnly  bool
 }
 
 func (q *Queries) AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error) {
@@ -42,6 +50,9 @@ func (q *Queries) AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error)
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.Link,
+		arg.Description,
+		arg.PublicOnly,
 	)
 	var i Feed
 	err := row.Scan(
@@ -52,6 +63,22 @@ func (q *Queries) AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error)
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ClaimedBy,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.LastStatusCode,
+		&i.ConsecutiveFailures,
+		&i.NextFetchAt,
+		&i.Link,
+		&i.Description,
+		&i.NumericID,
+		&i.MinIntervalSeconds,
+		pq.Array(&i.SkipHours),
+		pq.Array(&i.SkipDays),
+		&i.PublicOnly,
 	)
 	return i, err
 }
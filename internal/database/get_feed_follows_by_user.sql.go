@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,8 +20,19 @@ SELECT
     ff.updated_at,
     ff.user_id,
     ff.feed_id,
+    ff.category,
     f.name AS feed_name,
-    u.name AS user_name
+    f.url AS feed_url,
+    f.link AS feed_link,
+    u.name AS user_name,
+    (
+        SELECT COUNT(*) FROM posts AS p
+        WHERE p.feed_id = ff.feed_id
+            AND NOT EXISTS (
+                SELECT 1 FROM post_reads AS pr
+                WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+            )
+    ) AS unread_count
 FROM feed_follows AS ff
 INNER JOIN feeds AS f ON ff.feed_id = f.id
 INNER JOIN users AS u ON ff.user_id = u.id
@@ -29,13 +41,17 @@ ORDER BY f.name ASC
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
-	FeedName  string
-	UserName  string
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	FeedID      uuid.UUID
+	Category    sql.NullString
+	FeedName    string
+	FeedUrl     string
+	FeedLink    sql.NullString
+	UserName    string
+	UnreadCount int64
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
@@ -53,8 +69,12 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 			&i.UpdatedAt,
 			&i.UserID,
 			&i.FeedID,
+			&i.Category,
 			&i.FeedName,
+			&i.FeedUrl,
+			&i.FeedLink,
 			&i.UserName,
+			&i.UnreadCount,
 		); err != nil {
 			return nil, err
 		}
@@ -1,39 +0,0 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.29.0
-// source: get_next_feed_to_fetch.sql
-
-package database
-
-import (
-	"context"
-)
-
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT
-    id,
-    created_at,
-    updated_at,
-    name,
-    url,
-    user_id,
-    last_fetched_at
-FROM feeds
-ORDER BY last_fetched_at ASC NULLS FIRST
-LIMIT 1
-`
-
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Url,
-		&i.UserID,
-		&i.LastFetchedAt,
-	)
-	return i, err
-}
@@ -13,19 +13,28 @@ import (
 	"github.com/google/uuid"
 )
 
-const markFeedFetched = `-- name: MarkFeedFetched :exec
+const markFeedFetched = `-- name: MarkFeedFetched :execrows
 UPDATE feeds
-SET last_fetched_at = $1 AND updated_at = $2
-WHERE id = $3
+SET last_fetched_at = $1, updated_at = $2, claimed_by = NULL, claimed_until = NULL
+WHERE id = $3 AND claimed_by = $4
 `
 
 type MarkFeedFetchedParams struct {
 	LastFetchedAt sql.NullTime
 	UpdatedAt     time.Time
 	ID            uuid.UUID
+	ClaimedBy     sql.NullString
 }
 
-func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
-	_, err := q.db.ExecContext(ctx, markFeedFetched, arg.LastFetchedAt, arg.UpdatedAt, arg.ID)
-	return err
+func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markFeedFetched,
+		arg.LastFetchedAt,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.ClaimedBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
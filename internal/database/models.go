@@ -11,14 +11,39 @@ import (
 	"github.com/google/uuid"
 )
 
+type ApiToken struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	TokenHash  string
+	LastUsedAt sql.NullTime
+}
+
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID                  uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Name                string
+	Url                 string
+	UserID              uuid.UUID
+	LastFetchedAt       sql.NullTime
+	ClaimedBy           sql.NullString
+	ClaimedUntil        sql.NullTime
+	Etag                sql.NullString
+	LastModified        sql.NullString
+	LastError           sql.NullString
+	LastErrorAt         sql.NullTime
+	LastStatusCode      sql.NullInt32
+	ConsecutiveFailures int32
+	NextFetchAt         sql.NullTime
+	Link                sql.NullString
+	Description         sql.NullString
+	NumericID           int64
+	MinIntervalSeconds  sql.NullInt32
+	SkipHours           []int32
+	SkipDays            []string
+	PublicOnly          bool
 }
 
 type FeedFollow struct {
@@ -27,17 +52,58 @@ type FeedFollow struct {
 	UpdatedAt time.Time
 	UserID    uuid.UUID
 	FeedID    uuid.UUID
+	Category  sql.NullString
 }
 
 type Post struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       sql.NullString
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullString
-	FeedID      uuid.UUID
+	ID                 uuid.UUID
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Title              sql.NullString
+	Url                string
+	Description        sql.NullString
+	PublishedAtRaw     sql.NullString
+	FeedID             uuid.UUID
+	Author             sql.NullString
+	EnclosureUrl       sql.NullString
+	EnclosureType      sql.NullString
+	PublishedAt        time.Time
+	Guid               sql.NullString
+	Revision           int32
+	ItemID             int64
+	PublishedAtGuessed bool
+}
+
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostStar struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	StarredAt time.Time
+}
+
+type PrunedPost struct {
+	FeedID   uuid.UUID
+	Guid     string
+	PrunedAt time.Time
+}
+
+type SavedSearch struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	UserID          uuid.UUID
+	Name            string
+	Query           string
+	TsQuery         string
+	FeedIds         []uuid.UUID
+	SinceAt         sql.NullTime
+	SinceAgeSeconds sql.NullInt64
+	UntilAt         sql.NullTime
 }
 
 type User struct {
@@ -46,3 +112,17 @@ type User struct {
 	UpdatedAt time.Time
 	Name      string
 }
+
+type UserPassword struct {
+	UserID          uuid.UUID
+	PasswordHash    string
+	UpdatedAt       time.Time
+	FeverApiKeyHash sql.NullString
+}
+
+type WebSession struct {
+	TokenHash string
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -46,10 +46,29 @@ func main() {
 	cmds.register("agg", handlerAgg)
 	cmds.register("addfeed", middlewareLoggedIn(handlerAddFeed))
 	cmds.register("feeds", handlerShowFeeds)
+	cmds.register("feed-errors", handlerFeedErrors)
 	cmds.register("follow", middlewareLoggedIn(handlerFollow))
 	cmds.register("following", middlewareLoggedIn(handlerListFollows))
 	cmds.register("unfollow", middlewareLoggedIn(handlerUnFollow))
 	cmds.register("browse", middlewareLoggedIn(handlerBrowse))
+	cmds.register("read", middlewareLoggedIn(handlerRead))
+	cmds.register("unread", middlewareLoggedIn(handlerUnread))
+	cmds.register("mark-all-read", middlewareLoggedIn(handlerMarkAllRead))
+	cmds.register("star", middlewareLoggedIn(handlerStar))
+	cmds.register("unstar", middlewareLoggedIn(handlerUnstar))
+	cmds.register("starred", middlewareLoggedIn(handlerStarred))
+	cmds.register("search", middlewareLoggedIn(handlerSearch))
+	cmds.register("savesearch", middlewareLoggedIn(handlerSaveSearch))
+	cmds.register("unsavesearch", middlewareLoggedIn(handlerUnsaveSearch))
+	cmds.register("prune", handlerPrune)
+	cmds.register("serve", handlerServe)
+	cmds.register("passwd", middlewareLoggedIn(handlerPasswd))
+	cmds.register("create-token", middlewareLoggedIn(handlerCreateToken))
+	cmds.register("tokens", middlewareLoggedIn(handlerListTokens))
+	cmds.register("revoke-token", middlewareLoggedIn(handlerRevokeToken))
+	cmds.register("import-opml", middlewareLoggedIn(handlerImportOPML))
+	cmds.register("export-opml", middlewareLoggedIn(handlerExportOPML))
+	cmds.register("tui", middlewareLoggedIn(handlerTUI))
 
 	if len(os.Args) < 2 {
 		log.Fatalf("Please supply a command")
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    OPMLHead `xml:"head"`
+	Body    OPMLBody `xml:"body"`
+}
+
+type OPMLHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+	OwnerName   string `xml:"ownerName,omitempty"`
+}
+
+type OPMLBody struct {
+	Outlines []OPMLOutline `xml:"outline"`
+}
+
+type OPMLOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Category string        `xml:"category,attr,omitempty"`
+	Outlines []OPMLOutline `xml:"outline"`
+}
+
+// opmlSubscription is one feed found in an OPML document, with the folders
+// it was nested in joined into a category.
+type opmlSubscription struct {
+	Title    string
+	XMLURL   string
+	HTMLURL  string
+	Category string
+}
+
+// subscriptions flattens the outline tree. Folders nest as "News/Tech"; an
+// outline's own category attribute is used when it is not in a folder.
+func (o *OPML) subscriptions() []opmlSubscription {
+	var subs []opmlSubscription
+	var walk func(outlines []OPMLOutline, folders []string)
+	walk = func(outlines []OPMLOutline, folders []string) {
+		for _, outline := range outlines {
+			title := strings.TrimSpace(outline.Title)
+			if title == "" {
+				title = strings.TrimSpace(outline.Text)
+			}
+
+			if xmlURL := strings.TrimSpace(outline.XMLURL); xmlURL != "" {
+				category := strings.Join(folders, "/")
+				if category == "" {
+					category = firstOPMLCategory(outline.Category)
+				}
+				subs = append(subs, opmlSubscription{
+					Title:    title,
+					XMLURL:   xmlURL,
+					HTMLURL:  strings.TrimSpace(outline.HTMLURL),
+					Category: category,
+				})
+			}
+
+			if len(outline.Outlines) > 0 {
+				walk(outline.Outlines, append(folders[:len(folders):len(folders)], title))
+			}
+		}
+	}
+	walk(o.Body.Outlines, nil)
+	return subs
+}
+
+// firstOPMLCategory takes the first of OPML 2.0's comma separated category
+// paths, without its leading slash.
+func firstOPMLCategory(attr string) string {
+	first, _, _ := strings.Cut(attr, ",")
+	return strings.Trim(strings.TrimSpace(first), "/")
+}
+
+// buildOPML turns a user's follows into an OPML 2.0 document, nesting feeds
+// in one folder per level of their "News/Tech" style category.
+func buildOPML(title string, follows []database.GetFeedFollowsForUserRow, created time.Time) OPML {
+	doc := OPML{
+		Version: "2.0",
+		Head: OPMLHead{
+			Title:       title,
+			DateCreated: created.UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, follow := range follows {
+		outline := OPMLOutline{
+			Text:    follow.FeedName,
+			Title:   follow.FeedName,
+			Type:    "rss",
+			XMLURL:  follow.FeedUrl,
+			HTMLURL: follow.FeedLink.String,
+		}
+
+		outlines := &doc.Body.Outlines
+		if follow.Category.Valid && follow.Category.String != "" {
+			outline.Category = "/" + follow.Category.String
+			for _, folder := range strings.Split(follow.Category.String, "/") {
+				outlines = opmlFolder(outlines, folder)
+			}
+		}
+		*outlines = append(*outlines, outline)
+	}
+
+	return doc
+}
+
+// opmlFolder finds or appends the folder outline called name and returns a
+// pointer to its children.
+func opmlFolder(outlines *[]OPMLOutline, name string) *[]OPMLOutline {
+	for i := range *outlines {
+		if (*outlines)[i].XMLURL == "" && (*outlines)[i].Text == name {
+			return &(*outlines)[i].Outlines
+		}
+	}
+	*outlines = append(*outlines, OPMLOutline{Text: name, Title: name})
+	return &(*outlines)[len(*outlines)-1].Outlines
+}
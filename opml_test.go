@@ -0,0 +1,69 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+func TestOPMLRoundTrip(t *testing.T) {
+	follows := []database.GetFeedFollowsForUserRow{
+		{FeedName: "Loose", FeedUrl: "https://loose.example/feed"},
+		{
+			FeedName: "Tech",
+			FeedUrl:  "https://tech.example/rss",
+			FeedLink: sql.NullString{String: "https://tech.example/", Valid: true},
+			Category: sql.NullString{String: "News/Tech", Valid: true},
+		},
+		{
+			FeedName: "Daily",
+			FeedUrl:  "https://daily.example/atom.xml",
+			Category: sql.NullString{String: "News", Valid: true},
+		},
+		{
+			FeedName: "Gadgets",
+			FeedUrl:  "https://gadgets.example/feed",
+			Category: sql.NullString{String: "News/Tech", Valid: true},
+		},
+	}
+
+	data, err := xml.MarshalIndent(buildOPML("gator", follows, time.Now()), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc OPML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []opmlSubscription{
+		{Title: "Loose", XMLURL: "https://loose.example/feed"},
+		{Title: "Tech", XMLURL: "https://tech.example/rss", HTMLURL: "https://tech.example/", Category: "News/Tech"},
+		{Title: "Gadgets", XMLURL: "https://gadgets.example/feed", Category: "News/Tech"},
+		{Title: "Daily", XMLURL: "https://daily.example/atom.xml", Category: "News"},
+	}
+	if got := doc.subscriptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip gave\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestFirstOPMLCategory(t *testing.T) {
+	tests := []struct {
+		attr string
+		want string
+	}{
+		{"", ""},
+		{"Blogs", "Blogs"},
+		{"/News/Tech", "News/Tech"},
+		{"/Blogs, /Other", "Blogs"},
+	}
+	for _, tt := range tests {
+		if got := firstOPMLCategory(tt.attr); got != tt.want {
+			t.Errorf("firstOPMLCategory(%q) = %q, want %q", tt.attr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errPrivateAddress is returned when a request made for an API or web user
+// would reach loopback, a private network or the machine gator runs on.
+var errPrivateAddress = errors.New("refusing to fetch from a private or local address")
+
+// publicClient only dials public addresses. The check runs on the resolved
+// address of every connection, redirects included, so neither DNS nor a
+// redirect can point it back inside. It ignores HTTP_PROXY, since the proxy
+// would be the only address checked.
+var publicClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 30 * time.Second,
+			Control: func(network, address string, c syscall.RawConn) error {
+				addrPort, err := netip.ParseAddrPort(address)
+				if err != nil {
+					return err
+				}
+				if !isPublicAddr(addrPort.Addr()) {
+					return fmt.Errorf("%w: %s", errPrivateAddress, addrPort.Addr())
+				}
+				return nil
+			},
+		}).DialContext,
+		ForceAttemptHTTP2:   true,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is carrier-grade NAT, private in all but name.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+type publicOnlyKey struct{}
+
+// publicOnly marks ctx so feed requests made with it go through publicClient.
+// Feeds added that way are marked public_only and agg fetches them the same
+// way, while the CLI fetches whatever the user running it asks for.
+func publicOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, publicOnlyKey{}, true)
+}
+
+func isPublicOnly(ctx context.Context) bool {
+	only, _ := ctx.Value(publicOnlyKey{}).(bool)
+	return only
+}
+
+// feedClient is the HTTP client for fetching feeds and pages under ctx.
+func feedClient(ctx context.Context) *http.Client {
+	if isPublicOnly(ctx) {
+		return publicClient
+	}
+	return &http.Client{}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// RDFFeed is an RSS 1.0 document. Unlike RSS 2.0 the items are siblings of
+// the channel rather than children of it.
+type RDFFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# RDF"`
+	Channel struct {
+		Title           string `xml:"title"`
+		Link            string `xml:"link"`
+		Description     string `xml:"description"`
+		UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+	} `xml:"channel"`
+	Items []RDFItem `xml:"item"`
+}
+
+type RDFItem struct {
+	About       string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+}
+
+func (f *RDFFeed) toRSSFeed() *RSSFeed {
+	var rss RSSFeed
+	rss.Channel.Title = f.Channel.Title
+	rss.Channel.Link = f.Channel.Link
+	rss.Channel.Description = f.Channel.Description
+	rss.Channel.UpdatePeriod = f.Channel.UpdatePeriod
+	rss.Channel.UpdateFrequency = f.Channel.UpdateFrequency
+
+	for _, item := range f.Items {
+		link := strings.TrimSpace(item.Link)
+		if link == "" {
+			link = item.About
+		}
+		rss.Channel.Item = append(rss.Channel.Item, RSSItem{
+			Title:       item.Title,
+			Link:        link,
+			Description: item.Description,
+			PubDate:     strings.TrimSpace(item.Date),
+			GUID:        item.About,
+			Author:      strings.TrimSpace(item.Creator),
+		})
+	}
+
+	return &rss
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"html"
 	"io"
 	"net/http"
+	"strings"
 )
 
 type RSSFeed struct {
@@ -14,47 +21,230 @@ type RSSFeed struct {
 		Link        string    `xml:"link"`
 		Description string    `xml:"description"`
 		Item        []RSSItem `xml:"item"`
+
+		// Publisher hints about how often to poll, see schedule.go.
+		TTL             string   `xml:"ttl"`
+		SkipHours       []string `xml:"skipHours>hour"`
+		SkipDays        []string `xml:"skipDays>day"`
+		UpdatePeriod    string   `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string   `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
 	} `xml:"channel"`
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	PubDate     string       `xml:"pubDate"`
+	GUID        string       `xml:"guid"`
+	Author      string       `xml:"author"`
+	Enclosure   RSSEnclosure `xml:"enclosure"`
+}
+
+// identity is the key a post is stored under within its feed. Items without
+// a guid fall back to their link, and failing that to their content. When
+// several guid-less items of the document share a link, see sharedLinks, the
+// title is hashed in with it so they don't collapse into one post.
+func (item RSSItem) identity(shared map[string]bool) string {
+	if guid := strings.TrimSpace(item.GUID); guid != "" {
+		return guid
+	}
+	if link := strings.TrimSpace(item.Link); link != "" {
+		if !shared[link] {
+			return link
+		}
+		sum := sha256.Sum256([]byte(link + "\x00" + item.Title))
+		return hex.EncodeToString(sum[:])
+	}
+	sum := sha256.Sum256([]byte(item.Title + "\x00" + item.Description))
+	return hex.EncodeToString(sum[:])
+}
+
+// sharedLinks returns the links that more than one guid-less item uses.
+func sharedLinks(items []RSSItem) map[string]bool {
+	seen := make(map[string]bool)
+	shared := make(map[string]bool)
+	for _, item := range items {
+		link := strings.TrimSpace(item.Link)
+		if strings.TrimSpace(item.GUID) != "" || link == "" {
+			continue
+		}
+		if seen[link] {
+			shared[link] = true
+		}
+		seen[link] = true
+	}
+	return shared
+}
+
+type RSSEnclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// errNotModified is returned by fetchFeedConditional when the server answers
+// 304 Not Modified to our cache validators.
+var errNotModified = errors.New("feed not modified")
+
+// errNotAFeed is returned by parseFeed for documents that are not RSS, RDF,
+// Atom or JSON Feed, most often an HTML page.
+var errNotAFeed = errors.New("not an RSS, Atom or JSON feed")
+
+// httpStatusError is returned for any response that is neither a success
+// nor a 304, so callers can record the status code.
+type httpStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %s", e.Status)
+}
+
+// cacheValidators are the response headers that let the next request for the
+// same feed be conditional.
+type cacheValidators struct {
+	ETag         string
+	LastModified string
 }
 
 func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+	rss, _, err := fetchFeedConditional(ctx, feedURL, cacheValidators{})
+	return rss, err
+}
+
+// fetchFeedConditional sends If-None-Match and If-Modified-Since from prev and
+// returns the validators of the new response alongside the feed.
+func fetchFeedConditional(ctx context.Context, feedURL string, prev cacheValidators) (*RSSFeed, cacheValidators, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, prev, err
 	}
 
 	req.Header.Set("User-Agent", "gator")
+	if prev.ETag != "" {
+		req.Header.Set("If-None-Match", prev.ETag)
+	}
+	if prev.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.LastModified)
+	}
 
-	client := http.Client{}
-	res, err := client.Do(req)
+	res, err := feedClient(ctx).Do(req)
 	if err != nil {
-		return &RSSFeed{}, err
+		return &RSSFeed{}, prev, err
 	}
 	defer res.Body.Close()
 
-	data, err := io.ReadAll(res.Body)
+	if res.StatusCode == http.StatusNotModified {
+		return nil, prev, errNotModified
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, prev, &httpStatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	validators := cacheValidators{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}
+
+	data, err := readBody(res.Body)
 	if err != nil {
-		return &RSSFeed{}, err
+		return &RSSFeed{}, prev, err
 	}
 
-	var rss RSSFeed
-	if err := xml.Unmarshal(data, &rss); err != nil {
-		return nil, err
+	rss, err := parseFeed(res.Header.Get("Content-Type"), data)
+	if err != nil {
+		return nil, prev, err
 	}
 
+	return unescapeFeed(rss), validators, nil
+}
+
+// unescapeFeed decodes the HTML entities feeds leave in titles and
+// descriptions.
+func unescapeFeed(rss *RSSFeed) *RSSFeed {
 	rss.Channel.Title = html.UnescapeString(rss.Channel.Title)
 	rss.Channel.Description = html.UnescapeString(rss.Channel.Description)
-	for _, item := range rss.Channel.Item {
-		item.Title = html.UnescapeString(item.Title)
-		item.Description = html.UnescapeString(item.Description)
+	for i := range rss.Channel.Item {
+		rss.Channel.Item[i].Title = html.UnescapeString(rss.Channel.Item[i].Title)
+		rss.Channel.Item[i].Description = html.UnescapeString(rss.Channel.Item[i].Description)
+	}
+	return rss
+}
+
+// maxBodySize caps how much of a feed or page gator downloads, anyone who can
+// add a feed can point it at an endless response.
+const maxBodySize = 10 << 20
+
+var errBodyTooLarge = fmt.Errorf("response is larger than %dMB", maxBodySize>>20)
+
+// readBody reads a response body of at most maxBodySize.
+func readBody(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBodySize {
+		return nil, errBodyTooLarge
+	}
+	return data, nil
+}
+
+// parseFeed works out the format from the Content-Type and the root element
+// of the document and decodes it, always returning the RSS item model.
+func parseFeed(contentType string, data []byte) (*RSSFeed, error) {
+	if isJSONFeed(contentType, data) {
+		var feed JSONFeed
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("%w: %v", errNotAFeed, err)
+		}
+		if !strings.Contains(feed.Version, "jsonfeed.org/version/") {
+			return nil, errNotAFeed
+		}
+		return feed.toRSSFeed(), nil
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNotAFeed, err)
+	}
+
+	if root.Space == atomNamespace && root.Local == "feed" {
+		var atom AtomFeed
+		if err := xml.Unmarshal(data, &atom); err != nil {
+			return nil, err
+		}
+		return atom.toRSSFeed(), nil
+	}
+
+	if root.Space == rdfNamespace && root.Local == "RDF" {
+		var rdf RDFFeed
+		if err := xml.Unmarshal(data, &rdf); err != nil {
+			return nil, err
+		}
+		return rdf.toRSSFeed(), nil
+	}
+
+	if root.Local != "rss" {
+		return nil, errNotAFeed
 	}
 
+	var rss RSSFeed
+	if err := xml.Unmarshal(data, &rss); err != nil {
+		return nil, err
+	}
 	return &rss, nil
 }
+
+func rootElement(data []byte) (xml.Name, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return xml.Name{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestItemIdentity(t *testing.T) {
+	items := []RSSItem{
+		{GUID: "urn:1", Link: "https://example.com/"},
+		{Title: "Own link", Link: "https://example.com/a"},
+		{Title: "Shared one", Link: "https://example.com/"},
+		{Title: "Shared two", Link: "https://example.com/"},
+		{Title: "No link", Description: "body"},
+	}
+	shared := sharedLinks(items)
+	if !shared["https://example.com/"] || len(shared) != 1 {
+		t.Fatalf("sharedLinks = %v, want only https://example.com/", shared)
+	}
+
+	keys := make(map[string]int)
+	for i, item := range items {
+		key := item.identity(shared)
+		if j, ok := keys[key]; ok {
+			t.Errorf("items %d and %d both have identity %q", j, i, key)
+		}
+		keys[key] = i
+	}
+	if got := items[0].identity(shared); got != "urn:1" {
+		t.Errorf("guid identity = %q, want urn:1", got)
+	}
+	if got := items[1].identity(shared); got != "https://example.com/a" {
+		t.Errorf("unshared link identity = %q, want the link", got)
+	}
+	if a, b := items[2].identity(shared), items[2].identity(shared); a != b {
+		t.Errorf("identity is not stable: %q, %q", a, b)
+	}
+}
+
+func TestParseJSONFeed(t *testing.T) {
+	data := []byte(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Example",
+		"home_page_url": "https://example.com/",
+		"items": [
+			{"id": "1", "url": "https://example.com/1", "title": "One", "content_html": "<p>one</p>",
+			 "date_published": "2024-03-05T10:00:00Z", "authors": [{"name": "Ann"}, {"name": "Bob"}],
+			 "attachments": [{"url": "https://example.com/1.mp3", "mime_type": "audio/mpeg"}]},
+			{"id": "https://example.com/2", "title": "Permalink id", "content_text": "two"},
+			{"id": 3, "title": "Number id", "summary": "three"},
+			{"id": "tag:example.com,2024:4", "external_url": "https://other.example/4", "title": "External"}
+		]
+	}`)
+	rss, err := parseFeed("text/plain", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rss.Channel.Title != "Example" || rss.Channel.Link != "https://example.com/" {
+		t.Errorf("channel = %q %q", rss.Channel.Title, rss.Channel.Link)
+	}
+	want := []RSSItem{
+		{
+			Title: "One", Link: "https://example.com/1", Description: "<p>one</p>",
+			PubDate: "2024-03-05T10:00:00Z", GUID: "1", Author: "Ann, Bob",
+			Enclosure: RSSEnclosure{URL: "https://example.com/1.mp3", Type: "audio/mpeg"},
+		},
+		{Title: "Permalink id", Link: "https://example.com/2", Description: "two", GUID: "https://example.com/2"},
+		{Title: "Number id", Description: "three", GUID: "3"},
+		{Title: "External", Link: "https://other.example/4", GUID: "tag:example.com,2024:4"},
+	}
+	if !reflect.DeepEqual(rss.Channel.Item, want) {
+		t.Errorf("items =\n%+v\nwant\n%+v", rss.Channel.Item, want)
+	}
+
+	if _, err := parseFeed("application/json", []byte(`{"title": "not a feed"}`)); !errors.Is(err, errNotAFeed) {
+		t.Errorf("JSON without a version: err = %v, want errNotAFeed", err)
+	}
+}
+
+func TestParseAtomFeed(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example</title>
+  <subtitle type="html">A &lt;b&gt;blog&lt;/b&gt;</subtitle>
+  <link rel="self" href="https://example.com/atom.xml"/>
+  <link href="https://example.com/"/>
+  <entry>
+    <id>urn:uuid:1</id>
+    <title>One</title>
+    <link rel="alternate" href="https://example.com/1"/>
+    <summary>Short</summary>
+    <content type="html">Long</content>
+    <published>2024-03-05T10:00:00Z</published>
+    <updated>2024-03-06T10:00:00Z</updated>
+    <author><name>Ann</name></author>
+    <author><name>Bob</name></author>
+  </entry>
+  <entry>
+    <id>https://example.com/2</id>
+    <title type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Two</div></title>
+    <content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>two</p></div></content>
+    <updated>2024-03-07T10:00:00Z</updated>
+  </entry>
+</feed>`)
+	rss, err := parseFeed("application/atom+xml", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rss.Channel.Title != "Example" || rss.Channel.Link != "https://example.com/" || rss.Channel.Description != "A <b>blog</b>" {
+		t.Errorf("channel = %q %q %q", rss.Channel.Title, rss.Channel.Link, rss.Channel.Description)
+	}
+	if len(rss.Channel.Item) != 2 {
+		t.Fatalf("got %d items, want 2", len(rss.Channel.Item))
+	}
+	want := RSSItem{
+		Title: "One", Link: "https://example.com/1", Description: "Short",
+		PubDate: "2024-03-05T10:00:00Z", GUID: "urn:uuid:1", Author: "Ann, Bob",
+	}
+	if got := rss.Channel.Item[0]; got != want {
+		t.Errorf("first entry = %+v, want %+v", got, want)
+	}
+	second := rss.Channel.Item[1]
+	if second.Link != "https://example.com/2" || second.PubDate != "2024-03-07T10:00:00Z" {
+		t.Errorf("second entry link, date = %q, %q, want the id and updated", second.Link, second.PubDate)
+	}
+	if !strings.Contains(second.Title, ">Two</div>") || !strings.Contains(second.Description, "<p>two</p>") {
+		t.Errorf("xhtml kept as markup: title %q, description %q", second.Title, second.Description)
+	}
+}
+
+func TestParseRDFFeed(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+  xmlns="http://purl.org/rss/1.0/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/"
+  xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">
+  <channel rdf:about="https://example.com/">
+    <title>Example</title>
+    <link>https://example.com/</link>
+    <description>A blog</description>
+    <sy:updatePeriod>daily</sy:updatePeriod>
+    <sy:updateFrequency>2</sy:updateFrequency>
+  </channel>
+  <item rdf:about="https://example.com/1">
+    <title>One</title>
+    <link>https://example.com/1?utm=rss</link>
+    <description>one</description>
+    <dc:date>2024-03-05T10:00:00Z</dc:date>
+    <dc:creator>Ann</dc:creator>
+  </item>
+  <item rdf:about="https://example.com/2">
+    <title>Two</title>
+  </item>
+</rdf:RDF>`)
+	rss, err := parseFeed("application/rdf+xml", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rss.Channel.Title != "Example" || rss.Channel.Link != "https://example.com/" || rss.Channel.Description != "A blog" {
+		t.Errorf("channel = %q %q %q", rss.Channel.Title, rss.Channel.Link, rss.Channel.Description)
+	}
+	if rss.Channel.UpdatePeriod != "daily" || rss.Channel.UpdateFrequency != "2" {
+		t.Errorf("update period, frequency = %q, %q", rss.Channel.UpdatePeriod, rss.Channel.UpdateFrequency)
+	}
+	want := []RSSItem{
+		{
+			Title: "One", Link: "https://example.com/1?utm=rss", Description: "one",
+			PubDate: "2024-03-05T10:00:00Z", GUID: "https://example.com/1", Author: "Ann",
+		},
+		{Title: "Two", Link: "https://example.com/2", GUID: "https://example.com/2"},
+	}
+	if !reflect.DeepEqual(rss.Channel.Item, want) {
+		t.Errorf("items =\n%+v\nwant\n%+v", rss.Channel.Item, want)
+	}
+
+	if _, err := parseFeed("text/html", []byte("<html><body>hi</body></html>")); !errors.Is(err, errNotAFeed) {
+		t.Errorf("HTML page: err = %v, want errNotAFeed", err)
+	}
+}
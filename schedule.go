@@ -0,0 +1,99 @@
+package main
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+// updatePeriods maps sy:updatePeriod values to their length.
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// scheduleHints reads the publisher's polling hints from a parsed feed so
+// they can be kept on the feed row and still apply after a 304. The
+// shortest interval is the longer of <ttl> and sy:updatePeriod.
+func scheduleHints(rss *RSSFeed) database.UpdateFeedScheduleHintsParams {
+	var hints database.UpdateFeedScheduleHintsParams
+	var interval time.Duration
+	if ttl, err := strconv.Atoi(strings.TrimSpace(rss.Channel.TTL)); err == nil && ttl > 0 {
+		interval = time.Duration(ttl) * time.Minute
+	}
+	if period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(rss.Channel.UpdatePeriod))]; ok {
+		frequency, err := strconv.Atoi(strings.TrimSpace(rss.Channel.UpdateFrequency))
+		if err != nil || frequency < 1 {
+			frequency = 1
+		}
+		interval = max(interval, period/time.Duration(frequency))
+	}
+	if interval > 0 {
+		hints.MinIntervalSeconds = sql.NullInt32{Int32: int32(interval / time.Second), Valid: true}
+	}
+
+	hints.SkipHours = []int32{}
+	for _, h := range rss.Channel.SkipHours {
+		if hour, err := strconv.Atoi(strings.TrimSpace(h)); err == nil {
+			// Some publishers number the hours 1-24.
+			hints.SkipHours = append(hints.SkipHours, int32(hour%24))
+		}
+	}
+	hints.SkipDays = []string{}
+	for _, d := range rss.Channel.SkipDays {
+		if day := strings.ToLower(strings.TrimSpace(d)); day != "" {
+			hints.SkipDays = append(hints.SkipDays, day)
+		}
+	}
+	return hints
+}
+
+// fetchInterval works out how long to wait before polling a feed again. It
+// starts from the average gap between its recent posts, never polls sooner
+// than the publisher's stored hints ask, and is clamped to
+// [minInterval, maxInterval].
+func fetchInterval(feed database.Feed, stats database.GetFeedPostingStatsRow, minInterval, maxInterval time.Duration) time.Duration {
+	interval := maxInterval
+	if stats.PostCount > 1 {
+		interval = time.Duration(stats.AvgIntervalSeconds * float64(time.Second))
+	}
+	if feed.MinIntervalSeconds.Valid {
+		interval = max(interval, time.Duration(feed.MinIntervalSeconds.Int32)*time.Second)
+	}
+	return min(max(interval, minInterval), maxInterval)
+}
+
+// skipAhead moves t forward, an hour at a time, out of any <skipHours> and
+// <skipDays> stored for the feed. Both are in GMT per the RSS spec.
+func skipAhead(t time.Time, feed database.Feed) time.Time {
+	if len(feed.SkipHours) == 0 && len(feed.SkipDays) == 0 {
+		return t
+	}
+
+	skipHours := make(map[int]bool)
+	for _, hour := range feed.SkipHours {
+		skipHours[int(hour)] = true
+	}
+	skipDays := make(map[string]bool)
+	for _, day := range feed.SkipDays {
+		skipDays[day] = true
+	}
+
+	// A week of hours is enough to get out of any valid combination, if
+	// every hour is skipped we give up rather than loop forever.
+	next := t
+	for i := 0; i < 7*24; i++ {
+		utc := next.UTC()
+		if !skipHours[utc.Hour()] && !skipDays[strings.ToLower(utc.Weekday().String())] {
+			return next
+		}
+		next = utc.Truncate(time.Hour).Add(time.Hour)
+	}
+	return t
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmartaudio/gator/internal/database"
+)
+
+func TestScheduleHints(t *testing.T) {
+	tests := []struct {
+		name        string
+		ttl         string
+		period      string
+		frequency   string
+		minInterval sql.NullInt32
+	}{
+		{"none", "", "", "", sql.NullInt32{}},
+		{"ttl", "60", "", "", sql.NullInt32{Int32: 3600, Valid: true}},
+		{"bad ttl", "soon", "", "", sql.NullInt32{}},
+		{"update period", "", "Daily", "", sql.NullInt32{Int32: 86400, Valid: true}},
+		{"update frequency", "", "hourly", "4", sql.NullInt32{Int32: 900, Valid: true}},
+		{"longer of both", "120", "hourly", "2", sql.NullInt32{Int32: 7200, Valid: true}},
+		{"unknown period", "", "fortnightly", "", sql.NullInt32{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rss RSSFeed
+			rss.Channel.TTL = tt.ttl
+			rss.Channel.UpdatePeriod = tt.period
+			rss.Channel.UpdateFrequency = tt.frequency
+			if got := scheduleHints(&rss).MinIntervalSeconds; got != tt.minInterval {
+				t.Errorf("MinIntervalSeconds = %+v, want %+v", got, tt.minInterval)
+			}
+		})
+	}
+
+	var rss RSSFeed
+	rss.Channel.SkipHours = []string{"0", " 7 ", "24", "noon"}
+	rss.Channel.SkipDays = []string{"Saturday", " sunday", ""}
+	hints := scheduleHints(&rss)
+	if want := []int32{0, 7, 0}; !reflect.DeepEqual(hints.SkipHours, want) {
+		t.Errorf("SkipHours = %v, want %v", hints.SkipHours, want)
+	}
+	if want := []string{"saturday", "sunday"}; !reflect.DeepEqual(hints.SkipDays, want) {
+		t.Errorf("SkipDays = %v, want %v", hints.SkipDays, want)
+	}
+}
+
+func TestFetchInterval(t *testing.T) {
+	const minInterval, maxInterval = 15 * time.Minute, 24 * time.Hour
+	tests := []struct {
+		name  string
+		hint  sql.NullInt32
+		stats database.GetFeedPostingStatsRow
+		want  time.Duration
+	}{
+		{"no posts", sql.NullInt32{}, database.GetFeedPostingStatsRow{}, maxInterval},
+		{"one post", sql.NullInt32{}, database.GetFeedPostingStatsRow{PostCount: 1, AvgIntervalSeconds: 60}, maxInterval},
+		{"posting rate", sql.NullInt32{}, database.GetFeedPostingStatsRow{PostCount: 10, AvgIntervalSeconds: 7200}, 2 * time.Hour},
+		{"clamped to min", sql.NullInt32{}, database.GetFeedPostingStatsRow{PostCount: 10, AvgIntervalSeconds: 30}, minInterval},
+		{"clamped to max", sql.NullInt32{}, database.GetFeedPostingStatsRow{PostCount: 2, AvgIntervalSeconds: 7 * 86400}, maxInterval},
+		{"hint wins", sql.NullInt32{Int32: 21600, Valid: true}, database.GetFeedPostingStatsRow{PostCount: 10, AvgIntervalSeconds: 3600}, 6 * time.Hour},
+		{"rate wins", sql.NullInt32{Int32: 3600, Valid: true}, database.GetFeedPostingStatsRow{PostCount: 10, AvgIntervalSeconds: 7200}, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := database.Feed{MinIntervalSeconds: tt.hint}
+			if got := fetchInterval(feed, tt.stats, minInterval, maxInterval); got != tt.want {
+				t.Errorf("fetchInterval = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipAhead(t *testing.T) {
+	// 5 March 2024 was a Tuesday.
+	tuesday := time.Date(2024, 3, 5, 3, 30, 0, 0, time.UTC)
+	allHours := make([]int32, 24)
+	for i := range allHours {
+		allHours[i] = int32(i)
+	}
+	tests := []struct {
+		name string
+		t    time.Time
+		feed database.Feed
+		want time.Time
+	}{
+		{"no hints", tuesday, database.Feed{}, tuesday},
+		{"allowed hour", tuesday, database.Feed{SkipHours: []int32{10}}, tuesday},
+		{"skipped hours", tuesday, database.Feed{SkipHours: []int32{2, 3, 4, 5}}, time.Date(2024, 3, 5, 6, 0, 0, 0, time.UTC)},
+		{"skipped day", tuesday, database.Feed{SkipDays: []string{"tuesday"}}, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"day and hours", tuesday, database.Feed{SkipHours: []int32{0, 1}, SkipDays: []string{"tuesday"}}, time.Date(2024, 3, 6, 2, 0, 0, 0, time.UTC)},
+		{"every hour", tuesday, database.Feed{SkipHours: allHours}, tuesday},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := skipAhead(tt.t, tt.feed); !got.Equal(tt.want) {
+				t.Errorf("skipAhead = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var errEmptySearch = errors.New("search query has no words in it")
+
+// toTSQuery turns search input into to_tsquery syntax. Words must all match,
+// "quoted words" must match as a phrase, a trailing * matches prefixes, a
+// leading - excludes a term and OR between terms matches either. Everything
+// else is dropped so user input can never be a tsquery syntax error.
+func toTSQuery(input string) (string, error) {
+	var out strings.Builder
+	pendingOr := false
+	for _, term := range splitSearchTerms(input) {
+		if term == "OR" {
+			pendingOr = out.Len() > 0
+			continue
+		}
+
+		negate := false
+		if strings.HasPrefix(term, "-") {
+			negate = true
+			term = term[1:]
+		}
+		phrase := false
+		if strings.HasPrefix(term, `"`) {
+			phrase = true
+			term = strings.Trim(term, `"`)
+		}
+		prefix := !phrase && strings.HasSuffix(term, "*")
+
+		words := strings.FieldsFunc(term, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) == 0 {
+			continue
+		}
+		lexeme := strings.Join(words, " <-> ")
+		if prefix {
+			lexeme += ":*"
+		}
+		if len(words) > 1 {
+			lexeme = "(" + lexeme + ")"
+		}
+		if negate {
+			lexeme = "!" + lexeme
+		}
+
+		if out.Len() > 0 {
+			if pendingOr {
+				out.WriteString(" | ")
+			} else {
+				out.WriteString(" & ")
+			}
+		}
+		out.WriteString(lexeme)
+		pendingOr = false
+	}
+	if out.Len() == 0 {
+		return "", errEmptySearch
+	}
+	return out.String(), nil
+}
+
+// joinSearchArgs puts command line arguments back into one query. The shell
+// has already eaten the quotes of `search "two words"`, so an argument with
+// spaces in it is taken as a phrase unless it uses the search syntax itself.
+func joinSearchArgs(args []string) string {
+	terms := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsFunc(arg, unicode.IsSpace) && !hasSearchSyntax(arg) {
+			arg = `"` + arg + `"`
+		}
+		terms[i] = arg
+	}
+	return strings.Join(terms, " ")
+}
+
+func hasSearchSyntax(arg string) bool {
+	for _, term := range strings.Fields(arg) {
+		if term == "OR" || strings.ContainsAny(term, `"*`) || strings.HasPrefix(term, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSearchTerms splits input on whitespace, keeping quoted phrases (and a
+// - in front of them) together. An unterminated quote runs to the end.
+func splitSearchTerms(input string) []string {
+	var terms []string
+	var term strings.Builder
+	inQuotes := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			term.WriteRune(r)
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms
+}
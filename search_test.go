@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToTSQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{"go", "go", nil},
+		{"go rust", "go & rust", nil},
+		{`"static typing"`, "(static <-> typing)", nil},
+		{"gorout*", "gorout:*", nil},
+		{"-java", "!java", nil},
+		{`-"big data" go`, "!(big <-> data) & go", nil},
+		{"go OR rust", "go | rust", nil},
+		{"OR go", "go", nil},
+		{"c++ & go", "c & go", nil},
+		{"it's", "(it <-> s)", nil},
+		{"", "", errEmptySearch},
+		{"!!! () &", "", errEmptySearch},
+	}
+	for _, tt := range tests {
+		got, err := toTSQuery(tt.input)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("toTSQuery(%q) error = %v, want %v", tt.input, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("toTSQuery(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+-- name: CreateApiToken :one
+INSERT INTO api_tokens (id, created_at, user_id, name, token_hash)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetApiTokensForUser :many
+SELECT * FROM api_tokens
+WHERE user_id = $1
+ORDER BY name ASC;
+
+-- name: DeleteApiToken :execrows
+DELETE FROM api_tokens
+WHERE user_id = $1 AND name = $2;
+
+-- name: GetUserByApiToken :one
+SELECT u.* FROM api_tokens AS t
+INNER JOIN users AS u ON u.id = t.user_id
+WHERE t.token_hash = $1;
+
+-- name: TouchApiToken :exec
+UPDATE api_tokens SET last_used_at = $2
+WHERE token_hash = $1;
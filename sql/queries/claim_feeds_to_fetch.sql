@@ -0,0 +1,17 @@
+-- name: ClaimFeedsToFetch :many
+UPDATE feeds
+SET claimed_by = $1, claimed_until = $2
+WHERE id IN (
+    SELECT id FROM feeds
+    WHERE (claimed_until IS NULL OR claimed_until < NOW())
+        AND (next_fetch_at IS NULL OR next_fetch_at <= NOW())
+    ORDER BY next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RenewFeedClaim :execrows
+UPDATE feeds
+SET claimed_until = $1
+WHERE id = $2 AND claimed_by = $3;
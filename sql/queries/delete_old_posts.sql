@@ -0,0 +1,18 @@
+-- name: DeleteOldPosts :one
+-- Leaves a tombstone in pruned_posts for each post, posts from before guids
+-- were kept are remembered by their url.
+WITH pruned AS (
+    DELETE FROM posts
+    WHERE published_at < $1
+        AND NOT EXISTS (
+            SELECT 1 FROM post_stars AS ps
+            WHERE ps.post_id = posts.id
+        )
+    RETURNING feed_id, COALESCE(guid, url) AS guid
+),
+tombstones AS (
+    INSERT INTO pruned_posts (feed_id, guid, pruned_at)
+    SELECT feed_id, guid, NOW() FROM pruned
+    ON CONFLICT (feed_id, guid) DO NOTHING
+)
+SELECT COUNT(*) FROM pruned;
@@ -0,0 +1,2 @@
+-- name: FeedNameExists :one
+SELECT EXISTS (SELECT 1 FROM feeds WHERE name = $1);
@@ -0,0 +1,68 @@
+-- name: GetFeverFeeds :many
+SELECT
+    f.id,
+    f.numeric_id,
+    f.name,
+    f.url,
+    f.link,
+    f.last_fetched_at,
+    ff.category
+FROM feed_follows AS ff
+INNER JOIN feeds AS f ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+ORDER BY f.name ASC;
+
+-- name: GetFeverItemCount :one
+SELECT COUNT(*) FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+WHERE ff.user_id = $1;
+
+-- name: GetFeverItems :many
+-- Fever pages forward from since_id in ascending order and backward from
+-- max_id in descending order, 50 items at a time.
+SELECT
+    p.item_id,
+    f.numeric_id AS feed_numeric_id,
+    p.title,
+    p.author,
+    p.description,
+    p.url,
+    p.published_at,
+    EXISTS (
+        SELECT 1 FROM post_reads AS pr
+        WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM post_stars AS ps
+        WHERE ps.post_id = p.id AND ps.user_id = ff.user_id
+    ) AS is_saved
+FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+INNER JOIN feeds AS f ON f.id = p.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(since_id)::bigint IS NULL OR p.item_id > sqlc.narg(since_id))
+    AND (sqlc.narg(max_id)::bigint IS NULL OR p.item_id < sqlc.narg(max_id))
+    AND (
+        COALESCE(cardinality(sqlc.arg(item_ids)::bigint[]), 0) = 0
+        OR p.item_id = ANY(sqlc.arg(item_ids)::bigint[])
+    )
+ORDER BY
+    CASE WHEN sqlc.narg(max_id)::bigint IS NOT NULL THEN p.item_id END DESC,
+    p.item_id ASC
+LIMIT 50;
+
+-- name: GetFeverUnreadItemIds :many
+SELECT p.item_id FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+WHERE ff.user_id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM post_reads AS pr
+        WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+    )
+ORDER BY p.item_id ASC;
+
+-- name: GetFeverSavedItemIds :many
+SELECT p.item_id FROM post_stars AS ps
+INNER JOIN posts AS p ON p.id = ps.post_id
+WHERE ps.user_id = $1
+ORDER BY p.item_id ASC;
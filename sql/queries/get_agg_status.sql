@@ -0,0 +1,10 @@
+-- name: GetAggStatus :one
+SELECT
+    COUNT(*) AS feeds,
+    COUNT(*) FILTER (WHERE next_fetch_at IS NULL OR next_fetch_at <= now()) AS due,
+    COUNT(*) FILTER (WHERE claimed_until > now()) AS fetching,
+    COUNT(*) FILTER (WHERE consecutive_failures > 0) AS failing,
+    COUNT(DISTINCT claimed_by) FILTER (WHERE claimed_until > now()) AS workers,
+    MAX(last_fetched_at)::timestamptz AS last_fetched_at,
+    MIN(next_fetch_at)::timestamptz AS next_fetch_at
+FROM feeds;
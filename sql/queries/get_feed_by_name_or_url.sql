@@ -0,0 +1,4 @@
+-- name: GetFeedByNameOrUrl :one
+SELECT * FROM feeds
+WHERE url = $1 OR name = $1
+LIMIT 1;
@@ -0,0 +1,4 @@
+-- name: GetFeedErrors :many
+SELECT * FROM feeds
+WHERE consecutive_failures > 0
+ORDER BY consecutive_failures DESC, name ASC;
@@ -5,8 +5,19 @@ SELECT
     ff.updated_at,
     ff.user_id,
     ff.feed_id,
+    ff.category,
     f.name AS feed_name,
-    u.name AS user_name
+    f.url AS feed_url,
+    f.link AS feed_link,
+    u.name AS user_name,
+    (
+        SELECT COUNT(*) FROM posts AS p
+        WHERE p.feed_id = ff.feed_id
+            AND NOT EXISTS (
+                SELECT 1 FROM post_reads AS pr
+                WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+            )
+    ) AS unread_count
 FROM feed_follows AS ff
 INNER JOIN feeds AS f ON ff.feed_id = f.id
 INNER JOIN users AS u ON ff.user_id = u.id
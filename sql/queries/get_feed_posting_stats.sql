@@ -0,0 +1,16 @@
+-- name: GetFeedPostingStats :one
+SELECT
+    COUNT(*)::int AS post_count,
+    COALESCE(
+        EXTRACT(EPOCH FROM MAX(published_at) - MIN(published_at)) / NULLIF(COUNT(*) - 1, 0),
+        0
+    )::float8 AS avg_interval_seconds
+FROM (
+    SELECT published_at
+    FROM posts
+    WHERE feed_id = $1
+        -- Posts without a usable date are dated when they were fetched.
+        AND NOT published_at_guessed
+    ORDER BY published_at DESC
+    LIMIT 20
+) AS recent;
@@ -0,0 +1,10 @@
+-- name: GetPostByID :one
+SELECT * FROM posts WHERE id = $1;
+
+-- name: GetPostForUser :one
+-- Only finds posts in feeds the user follows.
+SELECT sqlc.embed(p), f.name AS feed_name
+FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+INNER JOIN feeds AS f ON f.id = p.feed_id
+WHERE p.id = $1 AND ff.user_id = $2;
@@ -0,0 +1,57 @@
+-- name: GetTimelineForUser :many
+SELECT
+    sqlc.embed(p),
+    f.name AS feed_name,
+    EXISTS (
+        SELECT 1 FROM post_reads AS pr
+        WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM post_stars AS ps
+        WHERE ps.post_id = p.id AND ps.user_id = ff.user_id
+    ) AS is_starred
+FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+INNER JOIN feeds AS f ON f.id = p.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (
+        NOT sqlc.arg(unread_only)::bool
+        OR NOT EXISTS (
+            SELECT 1 FROM post_reads AS pr
+            WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+        )
+    )
+    AND (
+        NOT sqlc.arg(starred_only)::bool
+        OR EXISTS (
+            SELECT 1 FROM post_stars AS ps
+            WHERE ps.post_id = p.id AND ps.user_id = ff.user_id
+        )
+    )
+    AND (
+        COALESCE(cardinality(sqlc.arg(feed_ids)::uuid[]), 0) = 0
+        OR p.feed_id = ANY(sqlc.arg(feed_ids)::uuid[])
+    )
+    AND (sqlc.narg(since)::timestamptz IS NULL OR p.published_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamptz IS NULL OR p.published_at < sqlc.narg(until))
+    AND (
+        sqlc.narg(query)::text IS NULL
+        OR post_search_vector(p.title, p.description) @@ to_tsquery('english', sqlc.narg(query))
+    )
+    AND (
+        sqlc.narg(before_published_at)::timestamptz IS NULL
+        OR (p.published_at, p.id) < (sqlc.narg(before_published_at), sqlc.narg(before_id)::uuid)
+    )
+    AND (
+        sqlc.narg(after_published_at)::timestamptz IS NULL
+        OR (p.published_at, p.id) > (sqlc.narg(after_published_at), sqlc.narg(after_id)::uuid)
+    )
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' THEN p.title END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'fetched' THEN p.created_at END DESC,
+    CASE WHEN sqlc.arg(ascending)::bool THEN p.published_at END ASC,
+    CASE WHEN sqlc.arg(ascending)::bool THEN p.id END ASC,
+    p.published_at DESC,
+    p.id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
@@ -0,0 +1,66 @@
+-- name: GetGReaderStream :many
+SELECT
+    sqlc.embed(p),
+    f.name AS feed_name,
+    f.url AS feed_url,
+    f.link AS feed_link,
+    ff.category,
+    EXISTS (
+        SELECT 1 FROM post_reads AS pr
+        WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM post_stars AS ps
+        WHERE ps.post_id = p.id AND ps.user_id = ff.user_id
+    ) AS is_starred
+FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+INNER JOIN feeds AS f ON f.id = p.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_id)::uuid IS NULL OR p.feed_id = sqlc.narg(feed_id))
+    AND (sqlc.narg(category)::text IS NULL OR ff.category = sqlc.narg(category))
+    AND (
+        sqlc.narg(read)::bool IS NULL
+        OR sqlc.narg(read) = EXISTS (
+            SELECT 1 FROM post_reads AS pr
+            WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+        )
+    )
+    AND (
+        NOT sqlc.arg(starred_only)::bool
+        OR EXISTS (
+            SELECT 1 FROM post_stars AS ps
+            WHERE ps.post_id = p.id AND ps.user_id = ff.user_id
+        )
+    )
+    AND (sqlc.narg(since)::timestamptz IS NULL OR p.published_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamptz IS NULL OR p.published_at <= sqlc.narg(until))
+ORDER BY
+    CASE WHEN sqlc.arg(oldest_first)::bool THEN p.published_at END ASC,
+    CASE WHEN sqlc.arg(oldest_first)::bool THEN p.item_id END ASC,
+    p.published_at DESC,
+    p.item_id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: GetGReaderItems :many
+SELECT
+    sqlc.embed(p),
+    f.name AS feed_name,
+    f.url AS feed_url,
+    f.link AS feed_link,
+    ff.category,
+    EXISTS (
+        SELECT 1 FROM post_reads AS pr
+        WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM post_stars AS ps
+        WHERE ps.post_id = p.id AND ps.user_id = ff.user_id
+    ) AS is_starred
+FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+INNER JOIN feeds AS f ON f.id = p.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND p.item_id = ANY(sqlc.arg(item_ids)::bigint[])
+ORDER BY p.published_at DESC, p.item_id DESC;
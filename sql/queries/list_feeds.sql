@@ -0,0 +1,3 @@
+-- name: ListFeeds :many
+SELECT * FROM feeds
+ORDER BY name ASC;
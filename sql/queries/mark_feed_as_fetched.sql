@@ -1,4 +1,4 @@
--- name: MarkFeedFetched :exec
+-- name: MarkFeedFetched :execrows
 UPDATE feeds
-SET last_fetched_at = $1 AND updated_at = $2
-WHERE id = $3;
+SET last_fetched_at = $1, updated_at = $2, claimed_by = NULL, claimed_until = NULL
+WHERE id = $3 AND claimed_by = $4;
@@ -0,0 +1,44 @@
+-- name: MarkPostRead :exec
+INSERT INTO post_reads (user_id, post_id, read_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :exec
+DELETE FROM post_reads
+WHERE user_id = $1 AND post_id = $2;
+
+-- name: MarkAllPostsRead :execrows
+-- feed_ids, since, until and query narrow it down like GetTimelineForUser,
+-- for marking a saved search read.
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT ff.user_id, p.id, sqlc.arg(read_at)
+FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_id)::uuid IS NULL OR p.feed_id = sqlc.narg(feed_id))
+    AND (sqlc.narg(category)::text IS NULL OR ff.category = sqlc.narg(category))
+    AND (sqlc.narg(published_before)::timestamptz IS NULL OR p.published_at <= sqlc.narg(published_before))
+    AND (
+        NOT sqlc.arg(starred_only)::bool
+        OR EXISTS (
+            SELECT 1 FROM post_stars AS ps
+            WHERE ps.post_id = p.id AND ps.user_id = ff.user_id
+        )
+    )
+    AND (
+        COALESCE(cardinality(sqlc.arg(feed_ids)::uuid[]), 0) = 0
+        OR p.feed_id = ANY(sqlc.arg(feed_ids)::uuid[])
+    )
+    AND (sqlc.narg(since)::timestamptz IS NULL OR p.published_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamptz IS NULL OR p.published_at < sqlc.narg(until))
+    AND (
+        sqlc.narg(query)::text IS NULL
+        OR post_search_vector(p.title, p.description) @@ to_tsquery('english', sqlc.narg(query))
+    )
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: IsPostRead :one
+SELECT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE user_id = $1 AND post_id = $2
+) AS is_read;
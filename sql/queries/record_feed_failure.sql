@@ -0,0 +1,9 @@
+-- name: RecordFeedFailure :exec
+UPDATE feeds
+SET
+    last_error = $1,
+    last_error_at = $2,
+    last_status_code = $3,
+    consecutive_failures = consecutive_failures + 1,
+    next_fetch_at = $4
+WHERE id = $5;
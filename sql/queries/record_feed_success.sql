@@ -0,0 +1,4 @@
+-- name: RecordFeedSuccess :exec
+UPDATE feeds
+SET consecutive_failures = 0, next_fetch_at = $1
+WHERE id = $2;
@@ -0,0 +1,56 @@
+-- name: SaveSearch :one
+INSERT INTO saved_searches (id, created_at, updated_at, user_id, name, query, ts_query, feed_ids, since_at, since_age_seconds, until_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11
+)
+ON CONFLICT (user_id, name) DO UPDATE
+SET
+    updated_at = EXCLUDED.updated_at,
+    query = EXCLUDED.query,
+    ts_query = EXCLUDED.ts_query,
+    feed_ids = EXCLUDED.feed_ids,
+    since_at = EXCLUDED.since_at,
+    since_age_seconds = EXCLUDED.since_age_seconds,
+    until_at = EXCLUDED.until_at
+RETURNING *;
+
+-- name: GetSavedSearchByName :one
+SELECT * FROM saved_searches
+WHERE user_id = $1 AND name = $2;
+
+-- name: GetSavedSearchesForUser :many
+SELECT
+    ss.*,
+    (
+        SELECT COUNT(*) FROM posts AS p
+        INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id AND ff.user_id = ss.user_id
+        WHERE post_search_vector(p.title, p.description) @@ to_tsquery('english', ss.ts_query)
+            AND (cardinality(ss.feed_ids) = 0 OR p.feed_id = ANY(ss.feed_ids))
+            AND (ss.since_at IS NULL OR p.published_at >= ss.since_at)
+            AND (
+                ss.since_age_seconds IS NULL
+                OR p.published_at >= now() - ss.since_age_seconds * INTERVAL '1 second'
+            )
+            AND (ss.until_at IS NULL OR p.published_at < ss.until_at)
+            AND NOT EXISTS (
+                SELECT 1 FROM post_reads AS pr
+                WHERE pr.post_id = p.id AND pr.user_id = ss.user_id
+            )
+    ) AS unread_count
+FROM saved_searches AS ss
+WHERE ss.user_id = $1
+ORDER BY ss.name ASC;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches
+WHERE user_id = $1 AND name = $2;
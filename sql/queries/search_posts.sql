@@ -0,0 +1,18 @@
+-- name: SearchPostsForUser :many
+-- Matches are wrapped in \x01 and \x02 so the caller can pick how to
+-- highlight them once the text is sanitized.
+SELECT
+    sqlc.embed(p),
+    f.name AS feed_name,
+    ts_rank(post_search_vector(p.title, p.description), to_tsquery('english', sqlc.arg(query))) AS rank,
+    ts_headline('english', coalesce(p.title, ''), to_tsquery('english', sqlc.arg(query)),
+        E'StartSel=\x01, StopSel=\x02, HighlightAll=true') AS title_headline,
+    ts_headline('english', coalesce(p.description, ''), to_tsquery('english', sqlc.arg(query)),
+        E'StartSel=\x01, StopSel=\x02, MaxFragments=2, MaxWords=30, MinWords=10') AS description_headline
+FROM posts AS p
+INNER JOIN feed_follows AS ff ON ff.feed_id = p.feed_id
+INNER JOIN feeds AS f ON f.id = p.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND post_search_vector(p.title, p.description) @@ to_tsquery('english', sqlc.arg(query))
+ORDER BY rank DESC, p.published_at DESC, p.id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,4 @@
+-- name: UpdateFeedScheduleHints :exec
+UPDATE feeds
+SET min_interval_seconds = $1, skip_hours = $2, skip_days = $3
+WHERE id = $4;
@@ -0,0 +1,38 @@
+-- name: UpsertPost :one
+-- Items prune already deleted are skipped, like unchanged ones.
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, published_at_raw, feed_id, author, enclosure_url, enclosure_type, guid, published_at_guessed)
+SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+WHERE NOT EXISTS (
+    SELECT 1 FROM pruned_posts AS pp
+    WHERE pp.feed_id = $9 AND pp.guid = $13
+)
+ON CONFLICT (feed_id, guid) DO UPDATE
+SET
+    updated_at = EXCLUDED.updated_at,
+    title = EXCLUDED.title,
+    url = EXCLUDED.url,
+    description = EXCLUDED.description,
+    author = EXCLUDED.author,
+    enclosure_url = EXCLUDED.enclosure_url,
+    enclosure_type = EXCLUDED.enclosure_type,
+    revision = posts.revision + 1
+WHERE posts.title IS DISTINCT FROM EXCLUDED.title
+    OR posts.url IS DISTINCT FROM EXCLUDED.url
+    OR posts.description IS DISTINCT FROM EXCLUDED.description
+RETURNING *;
+
+-- name: AdoptPostGuid :exec
+-- Posts stored before guids were kept have none, the first item fetched
+-- with the same url takes one over instead of being stored again.
+UPDATE posts
+SET guid = sqlc.arg(guid)
+WHERE id = (
+    SELECT p.id FROM posts AS p
+    WHERE p.feed_id = sqlc.arg(feed_id) AND p.url = sqlc.arg(url) AND p.guid IS NULL
+    ORDER BY p.created_at ASC
+    LIMIT 1
+)
+    AND NOT EXISTS (
+        SELECT 1 FROM posts AS p
+        WHERE p.feed_id = sqlc.arg(feed_id) AND p.guid = sqlc.arg(guid)
+    );
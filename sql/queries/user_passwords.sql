@@ -0,0 +1,20 @@
+-- name: SetUserPassword :exec
+INSERT INTO user_passwords (user_id, password_hash, updated_at, fever_api_key_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    password_hash = EXCLUDED.password_hash,
+    updated_at = EXCLUDED.updated_at,
+    fever_api_key_hash = EXCLUDED.fever_api_key_hash;
+
+-- name: GetUserWithPassword :one
+SELECT u.*, up.password_hash
+FROM users AS u
+INNER JOIN user_passwords AS up ON up.user_id = u.id
+WHERE u.name = $1;
+
+-- name: GetUserByFeverApiKey :one
+SELECT u.*
+FROM users AS u
+INNER JOIN user_passwords AS up ON up.user_id = u.id
+WHERE up.fever_api_key_hash = $1;
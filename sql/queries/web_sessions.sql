@@ -0,0 +1,20 @@
+-- name: CreateWebSession :exec
+INSERT INTO web_sessions (token_hash, user_id, created_at, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetUserByWebSession :one
+SELECT u.* FROM web_sessions AS ws
+INNER JOIN users AS u ON u.id = ws.user_id
+WHERE ws.token_hash = $1 AND ws.expires_at > $2;
+
+-- name: DeleteWebSession :exec
+DELETE FROM web_sessions
+WHERE token_hash = $1;
+
+-- name: DeleteWebSessionsForUser :exec
+DELETE FROM web_sessions
+WHERE user_id = $1;
+
+-- name: DeleteExpiredWebSessions :exec
+DELETE FROM web_sessions
+WHERE expires_at <= $1;
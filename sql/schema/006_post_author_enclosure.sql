@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN author TEXT,
+ADD COLUMN enclosure_url TEXT,
+ADD COLUMN enclosure_type TEXT;
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN author,
+DROP COLUMN enclosure_url,
+DROP COLUMN enclosure_type;
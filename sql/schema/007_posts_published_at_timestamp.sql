@@ -0,0 +1,40 @@
+-- +goose Up
+ALTER TABLE posts
+RENAME COLUMN published_at TO published_at_raw;
+
+ALTER TABLE posts
+ADD COLUMN published_at TIMESTAMPTZ;
+
+-- Postgres understands most RFC 822 and RFC 3339 dates, anything it rejects
+-- falls back to the time the post was stored.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT id, published_at_raw FROM posts WHERE published_at_raw IS NOT NULL LOOP
+        BEGIN
+            UPDATE posts SET published_at = r.published_at_raw::timestamptz WHERE id = r.id;
+        EXCEPTION WHEN others THEN
+            NULL;
+        END;
+    END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+UPDATE posts SET published_at = created_at WHERE published_at IS NULL;
+
+ALTER TABLE posts
+ALTER COLUMN published_at SET NOT NULL;
+
+CREATE INDEX posts_feed_id_published_at_idx ON posts (feed_id, published_at DESC);
+
+-- +goose Down
+DROP INDEX posts_feed_id_published_at_idx;
+
+ALTER TABLE posts
+DROP COLUMN published_at;
+
+ALTER TABLE posts
+RENAME COLUMN published_at_raw TO published_at;
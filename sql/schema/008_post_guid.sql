@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN guid TEXT,
+ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
+
+-- Existing posts keep a NULL guid, an item's <guid> often isn't its <link>.
+-- The first item fetched with the same url adopts the post instead.
+
+ALTER TABLE posts
+DROP CONSTRAINT posts_url_key;
+
+ALTER TABLE posts
+ADD CONSTRAINT posts_feed_id_guid_key UNIQUE (feed_id, guid);
+
+CREATE INDEX posts_url_idx ON posts (url);
+
+-- +goose Down
+DROP INDEX posts_url_idx;
+
+ALTER TABLE posts
+DROP CONSTRAINT posts_feed_id_guid_key;
+
+ALTER TABLE posts
+ADD CONSTRAINT posts_url_key UNIQUE (url);
+
+ALTER TABLE posts
+DROP COLUMN guid,
+DROP COLUMN revision;
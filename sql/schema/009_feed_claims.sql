@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN claimed_by TEXT,
+ADD COLUMN claimed_until TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN claimed_by,
+DROP COLUMN claimed_until;
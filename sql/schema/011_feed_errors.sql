@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN last_error TEXT,
+ADD COLUMN last_error_at TIMESTAMPTZ,
+ADD COLUMN last_status_code INTEGER,
+ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN next_fetch_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN last_error,
+DROP COLUMN last_error_at,
+DROP COLUMN last_status_code,
+DROP COLUMN consecutive_failures,
+DROP COLUMN next_fetch_at;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX feeds_next_fetch_at_idx ON feeds (next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST);
+
+-- +goose Down
+DROP INDEX feeds_next_fetch_at_idx;
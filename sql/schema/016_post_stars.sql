@@ -0,0 +1,14 @@
+-- +goose Up
+-- A star goes away with its user or its post, prune leaves starred posts
+-- alone but deleting a feed takes its posts and their stars with it.
+CREATE TABLE post_stars (
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    starred_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, post_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE post_stars;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Titles weigh more than descriptions when ranking search results. The
+-- vector is only kept in the index, a stored column would be read back with
+-- every post.
+-- +goose StatementBegin
+CREATE FUNCTION post_search_vector(title TEXT, description TEXT)
+RETURNS TSVECTOR
+LANGUAGE sql IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('english', coalesce(title, '')), 'A')
+        || setweight(to_tsvector('english', coalesce(description, '')), 'B')
+$$;
+-- +goose StatementEnd
+
+CREATE INDEX posts_search_vector_idx ON posts USING GIN (post_search_vector(title, description));
+
+-- +goose Down
+DROP INDEX posts_search_vector_idx;
+DROP FUNCTION post_search_vector(TEXT, TEXT);
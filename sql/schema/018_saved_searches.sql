@@ -0,0 +1,23 @@
+-- +goose Up
+-- A saved search keeps the query as typed for display and its to_tsquery
+-- form for matching. An empty feed_ids matches every followed feed. The
+-- window starts either at since_at or since_age_seconds before now, so
+-- "the last week" keeps rolling forward.
+CREATE TABLE saved_searches (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL,
+    ts_query TEXT NOT NULL,
+    feed_ids UUID[] NOT NULL DEFAULT '{}',
+    since_at TIMESTAMPTZ,
+    since_age_seconds BIGINT,
+    until_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (user_id, name)
+);
+
+-- +goose Down
+DROP TABLE saved_searches;
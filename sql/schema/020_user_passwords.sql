@@ -0,0 +1,11 @@
+-- +goose Up
+-- Passwords are only needed by the sync APIs, the CLI never asks for one.
+CREATE TABLE user_passwords (
+    user_id UUID PRIMARY KEY,
+    password_hash TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE user_passwords;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Google Reader clients address items by a 64 bit integer.
+ALTER TABLE posts ADD COLUMN item_id BIGSERIAL NOT NULL UNIQUE;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN item_id;
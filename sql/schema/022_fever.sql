@@ -0,0 +1,9 @@
+-- +goose Up
+-- Fever clients send md5("<user>:<password>") on every request and address
+-- feeds by integer ids.
+ALTER TABLE user_passwords ADD COLUMN fever_api_key TEXT UNIQUE;
+ALTER TABLE feeds ADD COLUMN numeric_id BIGSERIAL NOT NULL UNIQUE;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN numeric_id;
+ALTER TABLE user_passwords DROP COLUMN fever_api_key;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- gator tui listens on this channel to show posts as soon as agg stores them.
+-- +goose StatementBegin
+CREATE FUNCTION notify_new_post() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('gator_new_posts', NEW.feed_id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER posts_notify_new_post
+AFTER INSERT ON posts
+FOR EACH ROW EXECUTE FUNCTION notify_new_post();
+
+-- +goose Down
+DROP TRIGGER posts_notify_new_post ON posts;
+DROP FUNCTION notify_new_post();
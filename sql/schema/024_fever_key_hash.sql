@@ -0,0 +1,12 @@
+-- +goose Up
+-- Keep only a SHA-256 of each Fever key, like api_tokens, so reading the
+-- table doesn't hand out working credentials.
+ALTER TABLE user_passwords RENAME COLUMN fever_api_key TO fever_api_key_hash;
+UPDATE user_passwords
+SET fever_api_key_hash = encode(sha256(convert_to(fever_api_key_hash, 'UTF8')), 'hex')
+WHERE fever_api_key_hash IS NOT NULL;
+
+-- +goose Down
+-- The keys can't be recovered, run gator passwd again after going back.
+UPDATE user_passwords SET fever_api_key_hash = NULL;
+ALTER TABLE user_passwords RENAME COLUMN fever_api_key_hash TO fever_api_key;
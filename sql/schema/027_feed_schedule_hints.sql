@@ -0,0 +1,42 @@
+-- +goose Up
+-- The publisher's polling hints, kept from the last time the feed was parsed
+-- so they still apply when the server answers 304. min_interval_seconds is
+-- the longer of <ttl> and sy:updatePeriod / sy:updateFrequency.
+ALTER TABLE feeds
+ADD COLUMN min_interval_seconds INTEGER,
+ADD COLUMN skip_hours INTEGER[] NOT NULL DEFAULT '{}',
+ADD COLUMN skip_days TEXT[] NOT NULL DEFAULT '{}';
+
+-- Posts whose date couldn't be parsed are dated when they were fetched, which
+-- would throw off the posting stats. Anything Postgres can't read either is
+-- taken to be one of them.
+ALTER TABLE posts
+ADD COLUMN published_at_guessed BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE posts SET published_at_guessed = true WHERE published_at_raw IS NULL;
+
+-- +goose StatementBegin
+DO $$
+DECLARE
+    r RECORD;
+    t TIMESTAMPTZ;
+BEGIN
+    FOR r IN SELECT id, published_at_raw FROM posts WHERE published_at_raw IS NOT NULL LOOP
+        BEGIN
+            t := r.published_at_raw::timestamptz;
+        EXCEPTION WHEN others THEN
+            UPDATE posts SET published_at_guessed = true WHERE id = r.id;
+        END;
+    END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN published_at_guessed;
+
+ALTER TABLE feeds
+DROP COLUMN min_interval_seconds,
+DROP COLUMN skip_hours,
+DROP COLUMN skip_days;
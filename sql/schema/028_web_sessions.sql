@@ -0,0 +1,15 @@
+-- +goose Up
+-- Web UI sign-ins. The cookie holds a random token, only its SHA-256 is
+-- stored, and signing out deletes the row.
+CREATE TABLE web_sessions (
+    token_hash TEXT PRIMARY KEY,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX web_sessions_user_id_idx ON web_sessions (user_id);
+
+-- +goose Down
+DROP TABLE web_sessions;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Feeds added over the API or the web UI are only ever fetched from public
+-- addresses, by agg too, so a redirect or a DNS change can't point them at
+-- the network gator runs in.
+ALTER TABLE feeds
+ADD COLUMN public_only BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN public_only;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- prune remembers the guid of every post it deletes, so the items still in
+-- the feed document are not stored again as new, unread posts.
+CREATE TABLE pruned_posts (
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    guid TEXT NOT NULL,
+    pruned_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (feed_id, guid)
+);
+
+-- +goose Down
+DROP TABLE pruned_posts;
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/term"
+)
+
+// terminal is the full-screen side of gator tui: raw keyboard input and a
+// frame drawn with ANSI escapes on the alternate screen.
+type terminal struct {
+	fd    int
+	saved *term.State
+	out   *bufio.Writer
+}
+
+func openTerminal() (*terminal, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, fmt.Errorf("tui needs a terminal")
+	}
+	saved, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	t := &terminal{fd: fd, saved: saved, out: bufio.NewWriterSize(os.Stdout, 64*1024)}
+	// Alternate screen, hidden cursor, no line wrapping.
+	t.out.WriteString("\x1b[?1049h\x1b[?25l\x1b[?7l")
+	t.out.Flush()
+	return t, nil
+}
+
+func (t *terminal) close() {
+	t.out.WriteString("\x1b[?7h\x1b[?25h\x1b[?1049l")
+	t.out.Flush()
+	term.Restore(t.fd, t.saved)
+}
+
+func (t *terminal) size() (width, height int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 80, 24
+	}
+	return width, height
+}
+
+// readKeys sends every key pressed to keys until stdin fails. Arrow and
+// paging keys arrive as escape sequences and are named like "up" and
+// "pgdown", control keys as "ctrl-d".
+func (t *terminal) readKeys(keys chan<- string) {
+	buf := make([]byte, 256)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+	}
+}
+
+var escapeKeys = map[string]string{
+	"\x1b[A": "up", "\x1b[B": "down", "\x1b[C": "right", "\x1b[D": "left",
+	"\x1bOA": "up", "\x1bOB": "down", "\x1bOC": "right", "\x1bOD": "left",
+	"\x1b[5~": "pgup", "\x1b[6~": "pgdown",
+	"\x1b[H": "home", "\x1b[F": "end", "\x1b[1~": "home", "\x1b[4~": "end",
+}
+
+func parseKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		if input[0] == 0x1b {
+			matched := false
+			for seq, name := range escapeKeys {
+				if strings.HasPrefix(string(input), seq) {
+					keys = append(keys, name)
+					input = input[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				if len(input) > 1 && (input[1] == '[' || input[1] == 'O') {
+					// A sequence we don't use, skip to its final byte.
+					input = input[2:]
+					for len(input) > 0 && (input[0] < 0x40 || input[0] > 0x7e) {
+						input = input[1:]
+					}
+					if len(input) > 0 {
+						input = input[1:]
+					}
+				} else {
+					keys = append(keys, "esc")
+					input = input[1:]
+				}
+			}
+			continue
+		}
+
+		switch b := input[0]; {
+		case b == '\r' || b == '\n':
+			keys = append(keys, "enter")
+		case b == '\t':
+			keys = append(keys, "tab")
+		case b == 0x7f:
+			keys = append(keys, "backspace")
+		case b < 0x20:
+			keys = append(keys, "ctrl-"+string(rune('a'+b-1)))
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, string(r))
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// cleanLine drops control characters, so a feed can't send its own escape
+// sequences to the terminal.
+func cleanLine(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// fit cuts or pads s to exactly width columns.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+		if width == 1 {
+			return "…"
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// wrapText breaks text into lines of at most width columns, at spaces where
+// it can.
+func wrapText(text string, width int) []string {
+	if width < 1 {
+		return nil
+	}
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		var line []rune
+		for _, word := range words {
+			w := []rune(word)
+			if len(line) > 0 && len(line)+1+len(w) > width {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			for len(w) > width {
+				lines = append(lines, string(w[:width]))
+				w = w[width:]
+			}
+			if len(line) > 0 {
+				line = append(line, ' ')
+			}
+			line = append(line, w...)
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// htmlBlocks are set apart by a blank line, htmlBreaks just start a new one.
+var (
+	htmlBlocks = map[string]bool{
+		"p": true, "div": true, "blockquote": true, "pre": true, "ul": true, "ol": true,
+		"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"table": true, "figure": true, "hr": true, "section": true,
+	}
+	htmlBreaks = map[string]bool{"br": true, "li": true, "tr": true}
+)
+
+// htmlToText turns a post description into plain text for the reader pane,
+// keeping paragraphs and list items on their own lines.
+func htmlToText(fragment string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), nil)
+	if err != nil {
+		return cleanLine(fragment)
+	}
+
+	var b strings.Builder
+	breakLine := func(name string) {
+		text := b.String()
+		if text == "" {
+			return
+		}
+		if !strings.HasSuffix(text, "\n") {
+			b.WriteString("\n")
+		}
+		if htmlBlocks[name] && !strings.HasSuffix(text, "\n\n") {
+			b.WriteString("\n")
+		}
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(cleanLine(n.Data))
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "head":
+				return
+			case "img":
+				if alt := htmlAttr(n, "alt"); alt != "" {
+					b.WriteString("[" + cleanLine(alt) + "]")
+				}
+				return
+			}
+			if htmlBlocks[n.Data] || htmlBreaks[n.Data] {
+				breakLine(n.Data)
+			}
+			if n.Data == "li" {
+				b.WriteString("• ")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && htmlBlocks[n.Data] {
+			breakLine(n.Data)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	text := strings.Join(lines, "\n")
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(text)
+}
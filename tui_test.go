@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"letters", "jk", []string{"j", "k"}},
+		{"utf-8", "é", []string{"é"}},
+		{"csi arrow", "\x1b[A", []string{"up"}},
+		{"ss3 arrow", "\x1bOB", []string{"down"}},
+		{"paging", "\x1b[5~\x1b[6~", []string{"pgup", "pgdown"}},
+		{"unused sequence", "\x1b[2~j", []string{"j"}},
+		{"lone escape", "\x1b", []string{"esc"}},
+		{"escape then key", "\x1bq", []string{"esc", "q"}},
+		{"enter", "\r", []string{"enter"}},
+		{"tab", "\t", []string{"tab"}},
+		{"backspace", "\x7f", []string{"backspace"}},
+		{"control", "\x04", []string{"ctrl-d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeys(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"a b c", 10, []string{"a b c"}},
+		{"hello world", 5, []string{"hello", "world"}},
+		{"hello world", 11, []string{"hello world"}},
+		{"abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"hi abcdefg", 4, []string{"hi", "abcd", "efg"}},
+		{"one\n\ntwo", 10, []string{"one", "", "two"}},
+		{"héllo wörld", 5, []string{"héllo", "wörld"}},
+		{"anything", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := wrapText(tt.text, tt.width); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"plain", "  spaced   out  ", "spaced out"},
+		{"paragraphs", "<p>One</p><p>Two</p>", "One\n\nTwo"},
+		{"line break", "a<br>b", "a\nb"},
+		{"list", "<p>Items:</p><ul><li>a</li><li>b</li></ul>", "Items:\n\n• a\n• b"},
+		{"entities", "Tom &amp; Jerry", "Tom & Jerry"},
+		{"image alt", `see <img src="x.png" alt="a cat"> here`, "see [a cat] here"},
+		{"script", "<script>alert(1)</script>hi", "hi"},
+		{"escape sequence", "a\x1b[31mb", "a[31mb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToText(tt.html); got != tt.want {
+				t.Errorf("htmlToText(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,583 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"html"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmartaudio/gator/internal/database"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+const (
+	webSessionCookie = "gator_session"
+	webLoginCookie   = "gator_login"
+	webSessionLength = 30 * 24 * time.Hour
+	webPageSize      = 30
+	webSummaryLength = 300
+)
+
+// webUI is the HTML reader gator serve puts at /. It signs in with the
+// password from gator passwd and keeps a random session token in a cookie.
+// Signing out, or changing the password, deletes the session.
+type webUI struct {
+	s     *state
+	pages map[string]*template.Template
+	// ugc keeps the markup of a post, text reduces it to plain text for
+	// titles and summaries.
+	ugc  *bluemonday.Policy
+	text *bluemonday.Policy
+}
+
+// webSession is the signed in user of a request, with the token their forms
+// send back to prove they came from one of our pages.
+type webSession struct {
+	User database.User
+	CSRF string
+}
+
+// webPage is what every template is executed with. The sidebar fields are
+// filled in for every page but login.
+type webPage struct {
+	Title    string
+	Session  *webSession
+	CSRF     string // the login form's, signed in pages use Session.CSRF
+	Follows  []database.GetFeedFollowsForUserRow
+	Searches []database.GetSavedSearchesForUserRow
+	Selected string
+	Error    string
+	Next     string
+
+	Posts  []webPost
+	Older  string
+	All    bool
+	Feed   string
+	Search string
+	Post   webPost
+	Feeds  []webFeed
+}
+
+type webPost struct {
+	ID          string
+	Title       string
+	URL         string
+	FeedName    string
+	Author      string
+	PublishedAt time.Time
+	Summary     string
+	Content     template.HTML
+	Enclosure   string
+	Read        bool
+}
+
+type webFeed struct {
+	ID        string
+	Name      string
+	URL       string
+	Link      string
+	Following bool
+}
+
+func newWebUI(s *state) *webUI {
+	funcs := template.FuncMap{
+		"date": func(t time.Time) string {
+			return t.Local().Format("2 Jan 2006 15:04")
+		},
+	}
+	pages := map[string]*template.Template{}
+	for _, name := range []string{"login", "timeline", "post", "feeds"} {
+		pages[name] = template.Must(
+			template.New(name).Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html"),
+		)
+	}
+
+	ugc := bluemonday.UGCPolicy()
+	ugc.AddTargetBlankToFullyQualifiedLinks(true)
+	return &webUI{
+		s:     s,
+		pages: pages,
+		ugc:   ugc,
+		text:  bluemonday.StrictPolicy(),
+	}
+}
+
+func (ui *webUI) register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /login", ui.handleLoginForm)
+	mux.HandleFunc("POST /login", ui.handleLogin)
+	mux.HandleFunc("POST /logout", ui.signedIn(ui.handleLogout))
+	mux.HandleFunc("GET /{$}", ui.signedIn(ui.handleTimeline))
+	mux.HandleFunc("GET /posts/{postID}", ui.signedIn(ui.handlePost))
+	mux.HandleFunc("POST /posts/{postID}/read", ui.signedIn(ui.handleMarkRead))
+	mux.HandleFunc("POST /posts/{postID}/unread", ui.signedIn(ui.handleMarkUnread))
+	mux.HandleFunc("POST /mark-all-read", ui.signedIn(ui.handleMarkAllRead))
+	mux.HandleFunc("GET /feeds", ui.signedIn(ui.handleFeeds))
+	mux.HandleFunc("POST /follow", ui.signedIn(ui.handleFollowURL))
+	mux.HandleFunc("POST /feeds/{feedID}/follow", ui.signedIn(ui.handleFollow))
+	mux.HandleFunc("POST /feeds/{feedID}/unfollow", ui.signedIn(ui.handleUnfollow))
+}
+
+// signedIn sends visitors without a valid session cookie to the login page,
+// and rejects form posts that don't carry the session's CSRF token.
+func (ui *webUI) signedIn(handler func(w http.ResponseWriter, r *http.Request, sess webSession)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(webSessionCookie)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		user, err := ui.s.db.GetUserByWebSession(r.Context(),
+			database.GetUserByWebSessionParams{
+				TokenHash: hashAPIToken(cookie.Value),
+				ExpiresAt: time.Now(),
+			},
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.SetCookie(w, &http.Cookie{Name: webSessionCookie, Path: "/", MaxAge: -1})
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			webError(w, err)
+			return
+		}
+
+		sess := webSession{User: user, CSRF: csrfToken(cookie.Value)}
+		if r.Method == http.MethodPost && !sameToken(r.PostFormValue("csrf"), sess.CSRF) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		handler(w, r, sess)
+	}
+}
+
+// csrfToken is tied to the session, so it changes with every sign in.
+func csrfToken(session string) string {
+	sum := sha256.Sum256([]byte("gator-csrf\x00" + session))
+	return hex.EncodeToString(sum[:])
+}
+
+func sameToken(sent, want string) bool {
+	return want != "" && subtle.ConstantTimeCompare([]byte(sent), []byte(want)) == 1
+}
+
+// handleLoginForm hands out a random token in a cookie and in the form, and
+// handleLogin only accepts a post that sends both, so another site can't
+// sign a browser in to an account of its choosing.
+func (ui *webUI) handleLoginForm(w http.ResponseWriter, r *http.Request) {
+	ui.renderLogin(w, r, http.StatusOK, "")
+}
+
+func (ui *webUI) renderLogin(w http.ResponseWriter, r *http.Request, code int, msg string) {
+	token := rand.Text()
+	http.SetCookie(w, &http.Cookie{
+		Name:     webLoginCookie,
+		Value:    token,
+		Path:     "/login",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	ui.render(w, code, "login", webPage{Title: "Sign in", Error: msg, CSRF: token})
+}
+
+func (ui *webUI) handleLogin(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(webLoginCookie)
+	if err != nil || !sameToken(r.PostFormValue("csrf"), cookie.Value) {
+		ui.renderLogin(w, r, http.StatusForbidden, "The sign in form expired, please try again")
+		return
+	}
+
+	name := r.PostFormValue("name")
+	user, err := ui.s.db.GetUserWithPassword(r.Context(), name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		webError(w, err)
+		return
+	}
+	if err != nil || !checkPassword(r.PostFormValue("password"), user.PasswordHash) {
+		ui.renderLogin(w, r, http.StatusUnauthorized, "Wrong user name or password")
+		return
+	}
+
+	// Expired sessions are only ever looked at here, no need for a timer.
+	now := time.Now()
+	if err := ui.s.db.DeleteExpiredWebSessions(r.Context(), now); err != nil {
+		log.Printf("error deleting expired web sessions: %v", err)
+	}
+	token := rand.Text()
+	err = ui.s.db.CreateWebSession(r.Context(),
+		database.CreateWebSessionParams{
+			TokenHash: hashAPIToken(token),
+			UserID:    user.ID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(webSessionLength),
+		},
+	)
+	if err != nil {
+		webError(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: webLoginCookie, Path: "/login", MaxAge: -1})
+	http.SetCookie(w, &http.Cookie{
+		Name:     webSessionCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(webSessionLength.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (ui *webUI) handleLogout(w http.ResponseWriter, r *http.Request, sess webSession) {
+	if cookie, err := r.Cookie(webSessionCookie); err == nil {
+		if err := ui.s.db.DeleteWebSession(r.Context(), hashAPIToken(cookie.Value)); err != nil {
+			webError(w, err)
+			return
+		}
+	}
+	http.SetCookie(w, &http.Cookie{Name: webSessionCookie, Path: "/", MaxAge: -1})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// handleTimeline is browse for the browser: unread posts from every followed
+// feed, or from the feed or saved search picked in the sidebar.
+func (ui *webUI) handleTimeline(w http.ResponseWriter, r *http.Request, sess webSession) {
+	query := r.URL.Query()
+	opts := timelineOptions{
+		All:    query.Get("all") == "true",
+		Search: query.Get("search"),
+		Before: query.Get("before"),
+		Limit:  webPageSize,
+		Page:   1,
+	}
+	if feed := query.Get("feed"); feed != "" {
+		opts.Feeds = []string{feed}
+	}
+	params, err := timelineParams(ui.s, sess.User, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	posts, err := ui.s.db.GetTimelineForUser(r.Context(), params)
+	if err != nil {
+		webError(w, err)
+		return
+	}
+
+	page, err := ui.page(r, sess, "Unread")
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	page.All = opts.All
+	page.Feed = query.Get("feed")
+	if opts.All {
+		page.Title = "All posts"
+	}
+	if page.Feed != "" {
+		page.Selected = "feed:" + page.Feed
+		for _, follow := range page.Follows {
+			if follow.FeedUrl == page.Feed || follow.FeedName == page.Feed {
+				page.Feed, page.Title = follow.FeedUrl, follow.FeedName
+				page.Selected = "feed:" + follow.FeedUrl
+			}
+		}
+	}
+	if opts.Search != "" {
+		page.Search = opts.Search
+		page.Selected, page.Title = "search:"+opts.Search, opts.Search
+	}
+	for _, post := range posts {
+		page.Posts = append(page.Posts, ui.toWebPost(post.Post, post.FeedName))
+	}
+	if len(posts) == opts.Limit {
+		older := r.URL.Query()
+		older.Set("before", posts[len(posts)-1].Post.ID.String())
+		page.Older = "/?" + older.Encode()
+	}
+	ui.render(w, http.StatusOK, "timeline", page)
+}
+
+func (ui *webUI) handlePost(w http.ResponseWriter, r *http.Request, sess webSession) {
+	postID, ok := webPathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	row, err := ui.s.db.GetPostForUser(r.Context(),
+		database.GetPostForUserParams{ID: postID, UserID: sess.User.ID},
+	)
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	post := row.Post
+	read, err := ui.s.db.IsPostRead(r.Context(),
+		database.IsPostReadParams{UserID: sess.User.ID, PostID: post.ID},
+	)
+	if err != nil {
+		webError(w, err)
+		return
+	}
+
+	page, err := ui.page(r, sess, "")
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	for _, follow := range page.Follows {
+		if follow.FeedID == post.FeedID {
+			page.Selected = "feed:" + follow.FeedUrl
+		}
+	}
+	page.Post = ui.toWebPost(post, row.FeedName)
+	page.Post.Content = template.HTML(ui.ugc.Sanitize(post.Description.String))
+	page.Post.Read = read
+	page.Title = page.Post.Title
+	ui.render(w, http.StatusOK, "post", page)
+}
+
+func (ui *webUI) handleMarkRead(w http.ResponseWriter, r *http.Request, sess webSession) {
+	postID, ok := webPathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	err := ui.s.db.MarkPostRead(r.Context(),
+		database.MarkPostReadParams{UserID: sess.User.ID, PostID: postID, ReadAt: time.Now().UTC()},
+	)
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	redirectBack(w, r)
+}
+
+func (ui *webUI) handleMarkUnread(w http.ResponseWriter, r *http.Request, sess webSession) {
+	postID, ok := webPathUUID(w, r, "postID")
+	if !ok {
+		return
+	}
+	err := ui.s.db.MarkPostUnread(r.Context(),
+		database.MarkPostUnreadParams{UserID: sess.User.ID, PostID: postID},
+	)
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	redirectBack(w, r)
+}
+
+// handleMarkAllRead marks the posts of the feed field or of the saved search
+// in the search field, or of every followed feed when both are empty.
+func (ui *webUI) handleMarkAllRead(w http.ResponseWriter, r *http.Request, sess webSession) {
+	params := database.MarkAllPostsReadParams{
+		ReadAt: time.Now().UTC(),
+		UserID: sess.User.ID,
+	}
+	if feedURL := r.PostFormValue("feed"); feedURL != "" {
+		feed, err := ui.s.db.GetFeedsByUrl(r.Context(), feedURL)
+		if err != nil {
+			webError(w, err)
+			return
+		}
+		params.FeedID.UUID, params.FeedID.Valid = feed.ID, true
+	}
+	if name := r.PostFormValue("search"); name != "" {
+		saved, err := ui.s.db.GetSavedSearchByName(r.Context(),
+			database.GetSavedSearchByNameParams{UserID: sess.User.ID, Name: name},
+		)
+		if err != nil {
+			webError(w, err)
+			return
+		}
+		var timeline database.GetTimelineForUserParams
+		applySavedSearch(&timeline, saved)
+		params.Query = timeline.Query
+		params.FeedIds = timeline.FeedIds
+		params.Since = timeline.Since
+		params.Until = timeline.Until
+	}
+	if _, err := ui.s.db.MarkAllPostsRead(r.Context(), params); err != nil {
+		webError(w, err)
+		return
+	}
+	redirectBack(w, r)
+}
+
+// handleFeeds lists every feed in the database, to follow or unfollow.
+func (ui *webUI) handleFeeds(w http.ResponseWriter, r *http.Request, sess webSession) {
+	feeds, err := ui.s.db.ListFeeds(r.Context())
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	page, err := ui.page(r, sess, "Feeds")
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	page.Selected = "feeds"
+	page.Error = r.URL.Query().Get("error")
+
+	following := map[string]bool{}
+	for _, follow := range page.Follows {
+		following[follow.FeedID.String()] = true
+	}
+	for _, feed := range feeds {
+		page.Feeds = append(page.Feeds, webFeed{
+			ID:        feed.ID.String(),
+			Name:      feed.Name,
+			URL:       feed.Url,
+			Link:      feed.Link.String,
+			Following: following[feed.ID.String()],
+		})
+	}
+	ui.render(w, http.StatusOK, "feeds", page)
+}
+
+// handleFollowURL follows a feed already in the database by its feed or site
+// url, like gator follow.
+func (ui *webUI) handleFollowURL(w http.ResponseWriter, r *http.Request, sess webSession) {
+	rawURL := strings.TrimSpace(r.PostFormValue("url"))
+	feed, _, err := findFeedByURL(publicOnly(r.Context()), ui.s, rawURL)
+	if err != nil {
+		log.Printf("web: couldn't find a feed for %s: %v", rawURL, err)
+		http.Redirect(w, r, "/feeds?"+url.Values{"error": {"no feed found at " + rawURL}}.Encode(), http.StatusSeeOther)
+		return
+	}
+	_, err = followFeed(ui.s, sess.User, feed.ID, r.PostFormValue("category"))
+	if err != nil && !isUniqueViolation(err, "feed_follows_user_id_feed_id_key") {
+		webError(w, err)
+		return
+	}
+	http.Redirect(w, r, "/?"+url.Values{"feed": {feed.Url}}.Encode(), http.StatusSeeOther)
+}
+
+func (ui *webUI) handleFollow(w http.ResponseWriter, r *http.Request, sess webSession) {
+	feedID, ok := webPathUUID(w, r, "feedID")
+	if !ok {
+		return
+	}
+	_, err := followFeed(ui.s, sess.User, feedID, "")
+	if err != nil && !isUniqueViolation(err, "feed_follows_user_id_feed_id_key") {
+		webError(w, err)
+		return
+	}
+	redirectBack(w, r)
+}
+
+func (ui *webUI) handleUnfollow(w http.ResponseWriter, r *http.Request, sess webSession) {
+	feedID, ok := webPathUUID(w, r, "feedID")
+	if !ok {
+		return
+	}
+	err := ui.s.db.DeleteFeedFollow(r.Context(),
+		database.DeleteFeedFollowParams{UserID: sess.User.ID, FeedID: feedID},
+	)
+	if err != nil {
+		webError(w, err)
+		return
+	}
+	redirectBack(w, r)
+}
+
+// page starts a webPage with the sidebar filled in.
+func (ui *webUI) page(r *http.Request, sess webSession, title string) (webPage, error) {
+	follows, err := ui.s.db.GetFeedFollowsForUser(r.Context(), sess.User.ID)
+	if err != nil {
+		return webPage{}, err
+	}
+	searches, err := ui.s.db.GetSavedSearchesForUser(r.Context(), sess.User.ID)
+	if err != nil {
+		return webPage{}, err
+	}
+	return webPage{
+		Title:    title,
+		Session:  &sess,
+		Follows:  follows,
+		Searches: searches,
+		Next:     r.URL.RequestURI(),
+	}, nil
+}
+
+func (ui *webUI) toWebPost(post database.Post, feedName string) webPost {
+	title := html.UnescapeString(ui.text.Sanitize(post.Title.String))
+	if title == "" {
+		title = post.Url
+	}
+	summary := strings.Join(strings.Fields(html.UnescapeString(ui.text.Sanitize(post.Description.String))), " ")
+	if utf8.RuneCountInString(summary) > webSummaryLength {
+		summary = string([]rune(summary)[:webSummaryLength]) + "…"
+	}
+	return webPost{
+		ID:          post.ID.String(),
+		Title:       title,
+		URL:         post.Url,
+		FeedName:    feedName,
+		Author:      html.UnescapeString(ui.text.Sanitize(post.Author.String)),
+		PublishedAt: post.PublishedAt,
+		Summary:     summary,
+		Enclosure:   post.EnclosureUrl.String,
+	}
+}
+
+// render executes a page into a buffer first, so a template error is a clean
+// 500 rather than half a page.
+func (ui *webUI) render(w http.ResponseWriter, code int, name string, page webPage) {
+	var buf bytes.Buffer
+	if err := ui.pages[name].ExecuteTemplate(&buf, "layout", page); err != nil {
+		webError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	buf.WriteTo(w)
+}
+
+// redirectBack returns to the page a form was posted from, which it names in
+// its next field. Only local paths are followed.
+func redirectBack(w http.ResponseWriter, r *http.Request) {
+	next := r.PostFormValue("next")
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		next = "/"
+	}
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+func webPathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return uuid.UUID{}, false
+	}
+	return id, true
+}
+
+// webError logs err and keeps its details out of the page, like
+// respondServerError.
+func webError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	log.Printf("web error: %v", err)
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+}